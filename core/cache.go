@@ -53,3 +53,13 @@ type Cache interface {
 	//   - 底层存储删除失败
 	Delete(ctx context.Context, key string) error
 }
+
+// TTLCache 支持查询剩余生存时间的 Cache
+// TokenManager 在缺少过期时间记录（如升级前写入的 token）时借助它判断何时续期。
+type TTLCache interface {
+	Cache
+
+	// TTL 返回 key 的剩余生存时间
+	// key 不存在、已过期或查询失败时返回 false；永不过期时返回 0 与 true。
+	TTL(ctx context.Context, key string) (time.Duration, bool)
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 )
@@ -13,13 +14,37 @@ import (
 const (
 	DefaultBaseURL = "https://api.weixin.qq.com"
 	DefaultTimeout = 30 * time.Second
+
+	defaultTokenRetries = 1
 )
 
+// TokenRetryPolicy access_token 失效（40001/42001）时的刷新重试策略。
+// 零值表示启用，失效后刷新 token 并重放一次请求。
+type TokenRetryPolicy struct {
+	// Disabled 为 true 时不做刷新重试，直接返回微信错误
+	Disabled bool
+	// MaxRetries 单次请求最多刷新重试次数，<= 0 时使用默认值 1
+	MaxRetries int
+}
+
+func (p TokenRetryPolicy) retries() int {
+	if p.Disabled {
+		return 0
+	}
+	if p.MaxRetries <= 0 {
+		return defaultTokenRetries
+	}
+	return p.MaxRetries
+}
+
 type ClientConfig struct {
 	BaseURL       string
 	HTTPClient    *http.Client
 	TokenProvider AccessTokenProvider
 	Logger        *slog.Logger
+	TokenRetry    TokenRetryPolicy
+	Middlewares   []Middleware
+	Retry         RetryPolicy
 }
 
 type Client struct {
@@ -27,6 +52,9 @@ type Client struct {
 	baseURL       *url.URL
 	tokenProvider AccessTokenProvider
 	logger        *slog.Logger
+	tokenRetries  int
+	middlewares   []Middleware
+	retryPolicy   RetryPolicy
 }
 
 func NewClient(cfg ClientConfig) (*Client, error) {
@@ -54,6 +82,9 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 		baseURL:       parsedBaseURL,
 		tokenProvider: cfg.TokenProvider,
 		logger:        logger,
+		tokenRetries:  cfg.TokenRetry.retries(),
+		middlewares:   slices.Clone(cfg.Middlewares),
+		retryPolicy:   cfg.Retry.normalize(),
 	}, nil
 }
 
@@ -98,14 +129,22 @@ func (c *Client) logRequest(ctx context.Context, method, rawURL string, body []b
 	c.logger.LogAttrs(ctx, slog.LevelDebug, "http request", attrs...)
 }
 
-func (c *Client) logResponse(ctx context.Context, statusCode int, body []byte) {
+func (c *Client) logResponse(ctx context.Context, resp RawResponse) {
 	if !c.logger.Enabled(ctx, slog.LevelDebug) {
 		return
 	}
 
-	attrs := []slog.Attr{slog.Int("status", statusCode)}
-	if len(body) > 0 {
-		attrs = append(attrs, slog.String("body", string(body)))
+	attrs := []slog.Attr{slog.Int("status", resp.StatusCode)}
+	switch {
+	case len(resp.Body) == 0:
+	case IsJSONResponse(resp.Header, resp.Body) || isTextResponse(resp.Header):
+		attrs = append(attrs, slog.String("body", string(resp.Body)))
+	default:
+		// 二进制响应（图片、媒体文件）只记录类型与大小
+		attrs = append(attrs,
+			slog.String("content_type", resp.Header.Get("Content-Type")),
+			slog.Int("body_size", len(resp.Body)),
+		)
 	}
 	c.logger.LogAttrs(ctx, slog.LevelDebug, "http response", attrs...)
 }
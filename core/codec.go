@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime"
+	"net/http"
+	"strings"
 )
 
 type wechatErrorEnvelope struct {
@@ -36,7 +39,7 @@ func DecodeWechat[T any](statusCode int, body []byte) (T, error) {
 	return out, nil
 }
 
-func parseWechatError(body []byte) error {
+func parseWechatError(body []byte) *WechatError {
 	var envelope wechatErrorEnvelope
 	if err := json.Unmarshal(body, &envelope); err != nil {
 		return nil
@@ -53,3 +56,49 @@ func truncateBody(body []byte, max int) string {
 	}
 	return string(body[:max]) + "..."
 }
+
+// BinaryResponse 二进制响应，如小程序码图片、媒体文件
+type BinaryResponse struct {
+	ContentType string
+	Body        []byte
+}
+
+// DecodeBinary 解析成功时返回二进制内容、失败时返回 JSON 错误的接口响应。
+// 响应为 JSON 时转换为 *WechatError；没有 Content-Type 时按内容推断。
+func DecodeBinary(resp RawResponse) (BinaryResponse, error) {
+	if IsJSONResponse(resp.Header, resp.Body) {
+		if wechatErr := parseWechatError(resp.Body); wechatErr != nil {
+			return BinaryResponse{}, wechatErr
+		}
+		return BinaryResponse{}, fmt.Errorf("expected binary response, got json: %s", truncateBody(resp.Body, 256))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return BinaryResponse{}, fmt.Errorf("http status %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(resp.Body)
+	}
+	return BinaryResponse{ContentType: contentType, Body: resp.Body}, nil
+}
+
+// IsJSONResponse 根据 Content-Type 判断响应是否为 JSON。
+// 微信部分二进制接口出错时返回的 JSON 使用 text/plain，Content-Type 缺失或为文本类型时再按首字符判断；
+// body 可以只是响应开头的一部分。
+func IsJSONResponse(header http.Header, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return true
+	}
+	if mediaType != "" && !strings.HasPrefix(mediaType, "text/") {
+		return false
+	}
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+func isTextResponse(header http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "xml")
+}
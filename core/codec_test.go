@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"net/http"
 	"testing"
 )
 
@@ -46,3 +47,65 @@ func TestDecodeWechat(t *testing.T) {
 		}
 	})
 }
+
+func TestIsJSONResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        bool
+	}{
+		{name: "application/json", contentType: "application/json; charset=utf-8", body: `{}`, want: true},
+		{name: "text/plain json", contentType: "text/plain", body: ` {"errcode":40001}`, want: true},
+		{name: "missing content type", body: `{"errcode":40001}`, want: true},
+		{name: "image", contentType: "image/jpeg", body: `{"errcode":40001}`},
+		{name: "text/plain non json", contentType: "text/plain", body: `success`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+			if got := IsJSONResponse(header, []byte(tt.body)); got != tt.want {
+				t.Fatalf("IsJSONResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBinary(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+
+	t.Run("binary", func(t *testing.T) {
+		got, err := DecodeBinary(RawResponse{StatusCode: 200, Header: http.Header{"Content-Type": {"image/png"}}, Body: png})
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if got.ContentType != "image/png" || string(got.Body) != string(png) {
+			t.Fatalf("unexpected binary: %+v", got)
+		}
+	})
+
+	t.Run("detect content type", func(t *testing.T) {
+		got, err := DecodeBinary(RawResponse{StatusCode: 200, Body: png})
+		if err != nil || got.ContentType != "image/png" {
+			t.Fatalf("unexpected result: %+v %v", got, err)
+		}
+	})
+
+	t.Run("wechat error", func(t *testing.T) {
+		_, err := DecodeBinary(RawResponse{StatusCode: 200, Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte(`{"errcode":41030,"errmsg":"invalid page"}`)})
+		var we *WechatError
+		if !errors.As(err, &we) || we.ErrCode != 41030 {
+			t.Fatalf("expected WechatError, got %v", err)
+		}
+	})
+
+	t.Run("unexpected json", func(t *testing.T) {
+		if _, err := DecodeBinary(RawResponse{StatusCode: 200, Body: []byte(`{"errcode":0}`)}); err == nil {
+			t.Fatal("expected error for json success body")
+		}
+	})
+}
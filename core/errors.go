@@ -15,21 +15,27 @@ func (e *WechatError) Error() string {
 	return fmt.Sprintf("wechat error: [%d] %s", e.ErrCode, e.ErrMsg)
 }
 
+// ErrResponseTooLarge 响应体超过 MaxBodySize 限制。
+var ErrResponseTooLarge = errors.New("response body too large")
+
 func NewWechatError(code int, msg string) *WechatError {
 	return &WechatError{ErrCode: code, ErrMsg: msg}
 }
 
 const (
-	ErrCodeSuccess          = 0
-	ErrCodeBusy             = -1
-	ErrCodeInvalidToken     = 40001
-	ErrCodeExpiredToken     = 42001
-	ErrCodeInvalidAppID     = 40013
-	ErrCodeInvalidAppSecret = 40125
-	ErrCodeInvalidCode      = 40029
-	ErrCodeCodeUsed         = 40163
-	ErrCodeFreqLimit        = 45011
-	ErrCodeAPIUnauthorized  = 48001
+	ErrCodeSuccess             = 0
+	ErrCodeBusy                = -1
+	ErrCodeInvalidToken        = 40001
+	ErrCodeExpiredToken        = 42001
+	ErrCodeInvalidAppID        = 40013
+	ErrCodeInvalidAppSecret    = 40125
+	ErrCodeInvalidCode         = 40029
+	ErrCodeCodeUsed            = 40163
+	ErrCodeInvalidRefreshToken = 40030
+	ErrCodeExpiredRefreshToken = 42002
+	ErrCodeDailyQuotaLimit     = 45009
+	ErrCodeFreqLimit           = 45011
+	ErrCodeAPIUnauthorized     = 48001
 )
 
 func IsTokenError(err error) bool {
@@ -37,8 +43,12 @@ func IsTokenError(err error) bool {
 		return false
 	}
 	var we *WechatError
-	if !errors.As(err, &we) {
+	if !errors.As(err, &we) || we == nil {
 		return false
 	}
-	return we.ErrCode == ErrCodeInvalidToken || we.ErrCode == ErrCodeExpiredToken
+	return isTokenErrCode(we.ErrCode)
+}
+
+func isTokenErrCode(code int) bool {
+	return code == ErrCodeInvalidToken || code == ErrCodeExpiredToken
 }
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ShinyNito/FunkWechat/v2/core/utils"
+)
+
+// Locker 分布式锁接口
+// TokenManager 借助它保证多进程部署时只有一个实例向微信刷新 token，其余实例轮询共享缓存。
+type Locker interface {
+	// TryLock 尝试加锁，不阻塞等待
+	// 锁在 ttl 后自动释放，避免持锁进程崩溃导致死锁。
+	//
+	// 参数:
+	//   - ctx: 上下文
+	//   - key: 锁名
+	//   - ttl: 锁的最长持有时间
+	//
+	// 返回:
+	//   - bool: 是否成功获得锁
+	//   - error: 可能的错误
+	//
+	// 错误:
+	//   - 底层存储不可用
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// Unlock 释放锁
+	// 只释放当前 Locker 实例持有的锁；锁已过期或被他人持有时应静默成功。
+	//
+	// 参数:
+	//   - ctx: 上下文
+	//   - key: 锁名
+	//
+	// 返回:
+	//   - error: 可能的错误
+	//
+	// 错误:
+	//   - 底层存储不可用
+	Unlock(ctx context.Context, key string) error
+}
+
+// AtomicCache 支持原子条件写入与删除的 Cache
+// CacheLocker 在底层缓存实现该接口时可获得严格互斥，否则退化为尽力而为的互斥。
+type AtomicCache interface {
+	Cache
+
+	// SetNX 仅当 key 不存在时写入，返回是否写入成功
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+
+	// CompareAndDelete 仅当 key 的当前值等于 value 时删除，返回是否删除
+	CompareAndDelete(ctx context.Context, key string, value string) (bool, error)
+}
+
+// MemoryLocker 进程内锁实现，适用于单实例部署或测试
+// 与 CacheLocker 一致，每个实例有自己的 owner，只会释放自己持有的锁；
+// 通过 NewOwner 获得共享同一锁表的其他持有者。
+type MemoryLocker struct {
+	table *memoryLockTable
+	owner uint64
+}
+
+type memoryLockTable struct {
+	mu        sync.Mutex
+	locks     map[string]memoryLock
+	lastOwner uint64
+}
+
+type memoryLock struct {
+	owner     uint64
+	expiresAt time.Time
+}
+
+// NewMemoryLocker 创建进程内锁实例
+func NewMemoryLocker() *MemoryLocker {
+	table := &memoryLockTable{locks: make(map[string]memoryLock)}
+	return table.newLocker()
+}
+
+// NewOwner 返回与 l 共享锁表、但 owner 不同的锁实例
+func (l *MemoryLocker) NewOwner() *MemoryLocker {
+	return l.table.newLocker()
+}
+
+func (t *memoryLockTable) newLocker() *MemoryLocker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastOwner++
+	return &MemoryLocker{table: t, owner: t.lastOwner}
+}
+
+// TryLock 尝试加锁
+func (l *MemoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	l.table.mu.Lock()
+	defer l.table.mu.Unlock()
+
+	now := time.Now()
+	if lock, ok := l.table.locks[key]; ok && now.Before(lock.expiresAt) {
+		return false, nil
+	}
+	l.table.locks[key] = memoryLock{owner: l.owner, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// Unlock 释放锁，锁已被其他 owner 重新获取时不做任何操作
+func (l *MemoryLocker) Unlock(ctx context.Context, key string) error {
+	l.table.mu.Lock()
+	defer l.table.mu.Unlock()
+
+	if lock, ok := l.table.locks[key]; ok && lock.owner == l.owner {
+		delete(l.table.locks, key)
+	}
+	return nil
+}
+
+// CacheLocker 基于 Cache 的锁实现
+// 每个实例持有随机 owner 标识，只会释放自己持有的锁。
+// 底层 Cache 实现 AtomicCache（如 RedisCache、MemoryCache）时加锁与解锁均为原子操作。
+type CacheLocker struct {
+	cache Cache
+	owner string
+}
+
+// NewCacheLocker 创建基于 Cache 的锁实例
+func NewCacheLocker(cache Cache) (*CacheLocker, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("cache is required")
+	}
+	owner, err := utils.RandomString(16)
+	if err != nil {
+		return nil, fmt.Errorf("generate lock owner: %w", err)
+	}
+	return &CacheLocker{cache: cache, owner: owner}, nil
+}
+
+// TryLock 尝试加锁
+func (l *CacheLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if atomicCache, ok := l.cache.(AtomicCache); ok {
+		return atomicCache.SetNX(ctx, key, l.owner, ttl)
+	}
+
+	if _, held := l.cache.Get(ctx, key); held {
+		return false, nil
+	}
+	if err := l.cache.Set(ctx, key, l.owner, ttl); err != nil {
+		return false, err
+	}
+	// 非原子缓存：写入后回读确认，尽量缩小并发加锁的竞争窗口
+	owner, ok := l.cache.Get(ctx, key)
+	return ok && owner == l.owner, nil
+}
+
+// Unlock 释放锁
+func (l *CacheLocker) Unlock(ctx context.Context, key string) error {
+	if atomicCache, ok := l.cache.(AtomicCache); ok {
+		_, err := atomicCache.CompareAndDelete(ctx, key, l.owner)
+		return err
+	}
+
+	if owner, ok := l.cache.Get(ctx, key); !ok || owner != l.owner {
+		return nil
+	}
+	return l.cache.Delete(ctx, key)
+}
+
+var (
+	_ Locker = (*MemoryLocker)(nil)
+	_ Locker = (*CacheLocker)(nil)
+)
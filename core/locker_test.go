@@ -0,0 +1,223 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	locker := NewMemoryLocker()
+	ctx := context.Background()
+
+	ok, err := locker.TryLock(ctx, "k", 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _ = locker.TryLock(ctx, "k", time.Second)
+	assert.False(t, ok, "lock should be held")
+
+	time.Sleep(30 * time.Millisecond)
+	ok, _ = locker.TryLock(ctx, "k", time.Second)
+	assert.True(t, ok, "expired lock should be acquirable")
+
+	require.NoError(t, locker.Unlock(ctx, "k"))
+	ok, _ = locker.TryLock(ctx, "k", time.Second)
+	assert.True(t, ok)
+}
+
+func TestMemoryLockerUnlockChecksOwner(t *testing.T) {
+	a := NewMemoryLocker()
+	b := a.NewOwner()
+	ctx := context.Background()
+
+	ok, _ := a.TryLock(ctx, "k", 20*time.Millisecond)
+	require.True(t, ok)
+	ok, _ = b.TryLock(ctx, "k", time.Minute)
+	assert.False(t, ok, "lock held by another owner")
+
+	// a 的锁过期后被 b 获取，a 迟到的解锁不应释放 b 的锁
+	time.Sleep(30 * time.Millisecond)
+	ok, _ = b.TryLock(ctx, "k", time.Minute)
+	require.True(t, ok)
+	require.NoError(t, a.Unlock(ctx, "k"))
+	ok, _ = a.TryLock(ctx, "k", time.Minute)
+	assert.False(t, ok, "stale unlock must not release another owner's lock")
+
+	require.NoError(t, b.Unlock(ctx, "k"))
+	ok, _ = a.TryLock(ctx, "k", time.Minute)
+	assert.True(t, ok)
+}
+
+func TestCacheLocker(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache Cache
+	}{
+		{name: "atomic cache", cache: NewMemoryCache()},
+		{name: "plain cache", cache: newTokenTestCache()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			a, err := NewCacheLocker(tt.cache)
+			require.NoError(t, err)
+			b, err := NewCacheLocker(tt.cache)
+			require.NoError(t, err)
+
+			ok, err := a.TryLock(ctx, "lock", time.Minute)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = b.TryLock(ctx, "lock", time.Minute)
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			// 非持有者解锁不应释放锁
+			require.NoError(t, b.Unlock(ctx, "lock"))
+			ok, _ = b.TryLock(ctx, "lock", time.Minute)
+			assert.False(t, ok)
+
+			require.NoError(t, a.Unlock(ctx, "lock"))
+			ok, _ = b.TryLock(ctx, "lock", time.Minute)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestTokenManagerLockerAcrossInstances(t *testing.T) {
+	shared := NewMemoryCache()
+	var calls int32
+	fetcher := func(ctx context.Context) (TokenFetchResult, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(30 * time.Millisecond)
+		return TokenFetchResult{Token: "fresh", ExpiresIn: 7200}, nil
+	}
+
+	// 模拟多个进程：各自独立的 TokenManager 与 Locker，共享同一缓存
+	managers := make([]*TokenManager, 5)
+	for i := range managers {
+		locker, err := NewCacheLocker(shared)
+		require.NoError(t, err)
+		managers[i], err = NewTokenManager(TokenManagerConfig{
+			Cache:            shared,
+			CacheKey:         "token-key",
+			Fetcher:          fetcher,
+			Locker:           locker,
+			LockPollInterval: 5 * time.Millisecond,
+		})
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, m := range managers {
+		wg.Go(func() {
+			token, err := m.GetToken(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "fresh", token)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestTokenManagerLockerForceRefreshWaitsForNewToken(t *testing.T) {
+	shared := NewMemoryCache()
+	require.NoError(t, shared.Set(context.Background(), "token-key", "stale", 0))
+	require.NoError(t, shared.Set(context.Background(), "token-key"+lockKeySuffix, "other-instance", time.Minute))
+	locker, err := NewCacheLocker(shared)
+	require.NoError(t, err)
+
+	m, err := NewTokenManager(TokenManagerConfig{
+		Cache:    shared,
+		CacheKey: "token-key",
+		Fetcher: func(ctx context.Context) (TokenFetchResult, error) {
+			t.Error("fetcher should not be called while another instance holds the lock")
+			return TokenFetchResult{}, nil
+		},
+		Locker:           locker,
+		LockPollInterval: 5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = shared.Set(context.Background(), "token-key", "renewed", time.Hour)
+	}()
+
+	token, err := m.RefreshToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "renewed", token)
+}
+
+func TestTokenManagerLockerWaitTimeout(t *testing.T) {
+	shared := NewMemoryCache()
+	locker, err := NewCacheLocker(shared)
+	require.NoError(t, err)
+	require.NoError(t, shared.Set(context.Background(), "token-key"+lockKeySuffix, "other-instance", time.Minute))
+
+	m, err := NewTokenManager(TokenManagerConfig{
+		Cache:    shared,
+		CacheKey: "token-key",
+		Fetcher: func(ctx context.Context) (TokenFetchResult, error) {
+			return TokenFetchResult{Token: "fresh", ExpiresIn: 7200}, nil
+		},
+		Locker:           locker,
+		LockTTL:          20 * time.Millisecond,
+		LockPollInterval: 5 * time.Millisecond,
+		LockWaitTimeout:  30 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, err = m.GetToken(context.Background())
+	assert.Error(t, err)
+}
+
+func TestTokenManagerLockerHolderDies(t *testing.T) {
+	shared := NewMemoryCache()
+	locker, err := NewCacheLocker(shared)
+	require.NoError(t, err)
+	// 另一实例持锁后崩溃，锁只能等 TTL 过期释放
+	require.NoError(t, shared.Set(context.Background(), "token-key"+lockKeySuffix, "dead-instance", 50*time.Millisecond))
+
+	var calls int32
+	m, err := NewTokenManager(TokenManagerConfig{
+		Cache:    shared,
+		CacheKey: "token-key",
+		Fetcher: func(ctx context.Context) (TokenFetchResult, error) {
+			atomic.AddInt32(&calls, 1)
+			return TokenFetchResult{Token: "fresh", ExpiresIn: 7200}, nil
+		},
+		Locker:           locker,
+		LockTTL:          50 * time.Millisecond,
+		LockPollInterval: 5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	token, err := m.GetToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", token)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestNewTokenManagerRejectsLockWaitShorterThanTTL(t *testing.T) {
+	locker, err := NewCacheLocker(NewMemoryCache())
+	require.NoError(t, err)
+
+	_, err = NewTokenManager(TokenManagerConfig{
+		Cache:           NewMemoryCache(),
+		CacheKey:        "token-key",
+		Fetcher:         func(ctx context.Context) (TokenFetchResult, error) { return TokenFetchResult{}, nil },
+		Locker:          locker,
+		LockTTL:         10 * time.Second,
+		LockWaitTimeout: 5 * time.Second,
+	})
+	assert.Error(t, err)
+}
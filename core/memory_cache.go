@@ -86,6 +86,51 @@ func (c *MemoryCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// SetNX 仅当 key 不存在或已过期时设置缓存值
+func (c *MemoryCache) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if item, exists := c.items[key]; exists && !item.isExpired() {
+		return false, nil
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	c.items[key] = &cacheItem{value: value, expiresAt: expiresAt}
+	return true, nil
+}
+
+// CompareAndDelete 仅当缓存值等于 value 时删除
+func (c *MemoryCache) CompareAndDelete(ctx context.Context, key string, value string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.items[key]
+	if !exists || item.isExpired() || item.value != value {
+		return false, nil
+	}
+	delete(c.items, key)
+	return true, nil
+}
+
+// TTL 返回缓存项的剩余生存时间，永不过期时返回 0
+func (c *MemoryCache) TTL(ctx context.Context, key string) (time.Duration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, exists := c.items[key]
+	if !exists || item.isExpired() {
+		return 0, false
+	}
+	if item.expiresAt.IsZero() {
+		return 0, true
+	}
+	return time.Until(item.expiresAt), true
+}
+
 // Cleanup 清理过期缓存项（可选，用于定期清理）
 func (c *MemoryCache) Cleanup() {
 	c.mu.Lock()
@@ -98,5 +143,9 @@ func (c *MemoryCache) Cleanup() {
 	}
 }
 
-// 确保 MemoryCache 实现了 Cache 接口
-var _ Cache = (*MemoryCache)(nil)
+// 确保 MemoryCache 实现了 Cache、AtomicCache 与 TTLCache 接口
+var (
+	_ Cache       = (*MemoryCache)(nil)
+	_ AtomicCache = (*MemoryCache)(nil)
+	_ TTLCache    = (*MemoryCache)(nil)
+)
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// MiddlewareRequest 中间件可见的请求信息。
+// 每次实际发出的 HTTP 请求（包括 token 刷新后的重放）都会构造一份新的实例。
+type MiddlewareRequest struct {
+	// Method HTTP 方法
+	Method string
+	// Path 微信 API 逻辑路径，如 /cgi-bin/user/info
+	Path string
+	// Query 查询参数（包含 access_token），中间件可增删改
+	Query map[string]string
+	// Header 请求头（已包含 Content-Type），中间件可增删改
+	Header http.Header
+	// Body JSON 请求体；multipart 上传时为 nil
+	Body []byte
+	// Attempt 当前是第几次发送，从 1 开始
+	Attempt int
+}
+
+// MiddlewareResponse 中间件可见的响应信息。
+type MiddlewareResponse struct {
+	// Raw 原始响应，中间件可修改（如故障注入）；流式请求的二进制响应体不在 Raw.Body 中
+	Raw RawResponse
+	// WechatError 从响应体解析出的微信错误，errcode 为 0 或非 JSON 响应时为 nil。
+	// 中间件改写 Raw.Body 后会按新响应体重新解析，无需同时设置
+	WechatError *WechatError
+	// Err 发送或读取响应失败时的错误，此时 Raw 为零值
+	Err error
+	// Duration 本次 HTTP 往返耗时
+	Duration time.Duration
+}
+
+// Middleware 请求中间件接口
+// 可用于注入指标、链路追踪、自定义请求头、请求签名以及故障注入等。
+// 多个中间件按注册顺序执行 OnRequest，按逆序执行 OnResponse。
+type Middleware interface {
+	// OnRequest 请求发送前调用
+	// 可修改 req.Query / req.Header；返回的 context 用于后续发送与 OnResponse（如挂载 trace span）。
+	//
+	// 参数:
+	//   - ctx: 上下文
+	//   - req: 请求信息
+	//
+	// 返回:
+	//   - context.Context: 后续使用的上下文，返回 nil 时沿用传入的 ctx
+	//   - error: 非 nil 时中止请求，已执行过 OnRequest 的中间件仍会收到 OnResponse
+	OnRequest(ctx context.Context, req *MiddlewareRequest) (context.Context, error)
+
+	// OnResponse 响应读取完成或请求失败后调用
+	//
+	// 参数:
+	//   - ctx: OnRequest 返回的上下文
+	//   - req: 请求信息
+	//   - resp: 响应信息，可修改 resp.Raw 与 resp.WechatError
+	//
+	// 返回:
+	//   - error: 非 nil 时替换请求的错误结果
+	OnResponse(ctx context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) error
+}
+
+// MiddlewareFuncs 以函数形式实现 Middleware，未设置的阶段直接跳过。
+type MiddlewareFuncs struct {
+	Request  func(ctx context.Context, req *MiddlewareRequest) (context.Context, error)
+	Response func(ctx context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) error
+}
+
+func (m MiddlewareFuncs) OnRequest(ctx context.Context, req *MiddlewareRequest) (context.Context, error) {
+	if m.Request == nil {
+		return ctx, nil
+	}
+	return m.Request(ctx, req)
+}
+
+func (m MiddlewareFuncs) OnResponse(ctx context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) error {
+	if m.Response == nil {
+		return nil
+	}
+	return m.Response(ctx, req, resp)
+}
+
+var _ Middleware = MiddlewareFuncs{}
+
+// runRequestMiddlewares 依次执行 OnRequest，返回最终上下文与每个已进入中间件对应的上下文。
+func runRequestMiddlewares(ctx context.Context, middlewares []Middleware, req *MiddlewareRequest) (context.Context, []context.Context, error) {
+	ctxs := make([]context.Context, 0, len(middlewares))
+	for _, m := range middlewares {
+		next, err := m.OnRequest(ctx, req)
+		if err != nil {
+			return ctx, ctxs, err
+		}
+		if next != nil {
+			ctx = next
+		}
+		ctxs = append(ctxs, ctx)
+	}
+	return ctx, ctxs, nil
+}
+
+// runResponseMiddlewares 按逆序对已进入的中间件执行 OnResponse。
+func runResponseMiddlewares(middlewares []Middleware, ctxs []context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) {
+	for i := len(ctxs) - 1; i >= 0; i-- {
+		if err := middlewares[i].OnResponse(ctxs[i], req, resp); err != nil {
+			resp.Err = err
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type ctxKey struct{}
+
+func TestMiddlewareOrderAndAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "trace-1" {
+			t.Errorf("missing injected header")
+		}
+		if r.URL.Query().Get("sign") != "signed" {
+			t.Errorf("missing injected query")
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 45009, "errmsg": "reach max api daily quota limit"})
+	}))
+	defer server.Close()
+
+	var events []string
+	var gotErr *WechatError
+	outer := MiddlewareFuncs{
+		Request: func(ctx context.Context, req *MiddlewareRequest) (context.Context, error) {
+			events = append(events, "outer:req")
+			if req.Path != "/cgi-bin/menu/create" || req.Query["access_token"] != "token" {
+				t.Errorf("unexpected request: %s %v", req.Path, req.Query)
+			}
+			if !strings.Contains(string(req.Body), `"button"`) {
+				t.Errorf("unexpected body: %s", req.Body)
+			}
+			req.Header.Set("X-Trace", "trace-1")
+			return context.WithValue(ctx, ctxKey{}, "span"), nil
+		},
+		Response: func(ctx context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) error {
+			events = append(events, "outer:resp")
+			if ctx.Value(ctxKey{}) != "span" {
+				t.Errorf("context not propagated")
+			}
+			gotErr = resp.WechatError
+			return nil
+		},
+	}
+	inner := MiddlewareFuncs{
+		Request: func(ctx context.Context, req *MiddlewareRequest) (context.Context, error) {
+			events = append(events, "inner:req")
+			req.Query["sign"] = "signed"
+			return ctx, nil
+		},
+		Response: func(ctx context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) error {
+			events = append(events, "inner:resp")
+			return nil
+		},
+	}
+
+	client, err := NewClient(ClientConfig{
+		BaseURL:       server.URL,
+		TokenProvider: &staticTokenProvider{token: "token"},
+		Middlewares:   []Middleware{outer, inner},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	_, err = NewTypedRequest[struct{}](client).
+		Path("/cgi-bin/menu/create").
+		Body(map[string]any{"button": []any{}}).
+		Post(context.Background())
+	if err == nil {
+		t.Fatal("expected wechat error")
+	}
+
+	want := []string{"outer:req", "inner:req", "inner:resp", "outer:resp"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected order: %v", events)
+	}
+	if gotErr == nil || gotErr.ErrCode != 45009 {
+		t.Fatalf("unexpected wechat error: %v", gotErr)
+	}
+}
+
+func TestMiddlewareAbortAndFaultInjection(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "name": "ok"})
+	}))
+	defer server.Close()
+
+	errInjected := errors.New("injected")
+	abort := MiddlewareFuncs{
+		Request: func(ctx context.Context, req *MiddlewareRequest) (context.Context, error) {
+			return ctx, errInjected
+		},
+	}
+	client, err := NewClient(ClientConfig{BaseURL: server.URL, Middlewares: []Middleware{abort}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := client.Request().Path("/a").Get(context.Background()); !errors.Is(err, errInjected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("request should not be sent, got %d calls", calls)
+	}
+
+	rewrite := MiddlewareFuncs{
+		Response: func(ctx context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) error {
+			resp.Raw.Body = []byte(`{"errcode":-1,"errmsg":"system busy"}`)
+			return nil
+		},
+	}
+	client, err = NewClient(ClientConfig{BaseURL: server.URL, Middlewares: []Middleware{rewrite}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	_, err = NewTypedRequest[struct{}](client).Path("/a").Get(context.Background())
+	var we *WechatError
+	if !errors.As(err, &we) || we.ErrCode != ErrCodeBusy {
+		t.Fatalf("expected injected busy error, got %v", err)
+	}
+}
+
+func TestMiddlewareRewriteDrivesRetry(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/busy" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeBusy, "errmsg": "system busy"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "name": "ok"})
+	}))
+	defer server.Close()
+
+	rewrite := MiddlewareFuncs{
+		Response: func(ctx context.Context, req *MiddlewareRequest, resp *MiddlewareResponse) error {
+			if req.Path == "/busy" {
+				resp.Raw.Body = []byte(`{"errcode":0,"name":"recovered"}`)
+			} else {
+				resp.Raw.Body = []byte(`{"errcode":-1,"errmsg":"system busy"}`)
+			}
+			return nil
+		},
+	}
+	client, err := NewClient(ClientConfig{BaseURL: server.URL, Retry: fastRetryPolicy(), Middlewares: []Middleware{rewrite}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	_, err = NewTypedRequest[struct{}](client).Path("/ok").Get(context.Background())
+	var we *WechatError
+	if !errors.As(err, &we) || we.ErrCode != ErrCodeBusy {
+		t.Fatalf("expected injected busy error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("injected busy error should be retried, got %d calls", n)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	type resp struct {
+		Name string `json:"name"`
+	}
+	got, err := NewTypedRequest[resp](client).Path("/busy").Get(context.Background())
+	if err != nil || got.Name != "recovered" {
+		t.Fatalf("expected rewritten success, got %+v %v", got, err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("rewritten success should not be retried, got %d calls", n)
+	}
+}
@@ -0,0 +1,242 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+// FilePart multipart 上传中的单个文件
+type FilePart struct {
+	// Field 表单字段名，如 media
+	Field    string
+	FileName string
+	// ContentType 文件类型，为空时使用 application/octet-stream
+	ContentType string
+	// Reader 文件内容。实现 io.Seeker 时流式上传，token 刷新或重试时回到起始位置重放；
+	// 否则整个请求体先缓冲到内存。
+	Reader io.Reader
+	// Open 可选，每次发送时重新打开文件，设置后忽略 Reader
+	Open func() (io.ReadCloser, error)
+	// Size 文件大小，<= 0 表示未知（Reader 为 io.Seeker 时自动计算）。
+	// 所有文件大小已知时请求携带 Content-Length，否则使用分块传输。
+	Size int64
+}
+
+func (p FilePart) replayable() bool {
+	if p.Open != nil {
+		return true
+	}
+	_, ok := p.Reader.(io.Seeker)
+	return ok
+}
+
+// errBodyReplaced 重放请求时关闭上一次尚未写完的请求体
+var errBodyReplaced = errors.New("request body replaced by retry")
+
+// multipartBody 可多次生成的 multipart 请求体。
+// 所有文件都可重放时通过 io.Pipe 边读边发，否则缓冲完整载荷。
+type multipartBody struct {
+	parts    []FilePart
+	fields   map[string]string
+	keys     []string
+	boundary string
+	starts   []int64
+
+	buffered []byte
+	stream   bool
+	size     int64
+
+	pr   *io.PipeReader
+	done chan struct{}
+}
+
+func newMultipartBody(parts []FilePart, fields map[string]string) (*multipartBody, error) {
+	m := &multipartBody{
+		parts:    parts,
+		fields:   fields,
+		keys:     slices.Sorted(maps.Keys(fields)),
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+		size:     -1,
+	}
+	for i, part := range parts {
+		if part.Reader == nil && part.Open == nil {
+			return nil, fmt.Errorf("file part %d (%s): reader is required", i, part.Field)
+		}
+	}
+
+	if !slices.ContainsFunc(parts, func(p FilePart) bool { return !p.replayable() }) {
+		return m, m.prepareStream()
+	}
+
+	readers := make([]io.Reader, len(parts))
+	for i, part := range parts {
+		readers[i] = part.Reader
+	}
+	var buf bytes.Buffer
+	if err := m.write(&buf, readers); err != nil {
+		return nil, err
+	}
+	m.buffered = buf.Bytes()
+	m.size = int64(len(m.buffered))
+	return m, nil
+}
+
+func (m *multipartBody) contentType() string {
+	return "multipart/form-data; boundary=" + m.boundary
+}
+
+// prepareStream 记录各文件的起始位置，并在大小均已知时计算 Content-Length
+func (m *multipartBody) prepareStream() error {
+	m.stream = true
+	m.starts = make([]int64, len(m.parts))
+
+	total := int64(0)
+	known := true
+	for i, part := range m.parts {
+		size := part.Size
+		if part.Open == nil {
+			seeker := part.Reader.(io.Seeker)
+			start, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return fmt.Errorf("seek file %s: %w", part.FileName, err)
+			}
+			m.starts[i] = start
+			if size <= 0 {
+				end, err := seeker.Seek(0, io.SeekEnd)
+				if err != nil {
+					return fmt.Errorf("seek file %s: %w", part.FileName, err)
+				}
+				if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+					return fmt.Errorf("seek file %s: %w", part.FileName, err)
+				}
+				size = end - start
+			}
+		}
+		if size <= 0 && part.Open != nil {
+			known = false
+		}
+		total += size
+	}
+	if !known {
+		return nil
+	}
+
+	// 文件内容为空时写出的字节数即为 multipart 头部、分隔符与普通字段的总长度
+	var overhead countingWriter
+	if err := m.write(&overhead, nil); err != nil {
+		return err
+	}
+	m.size = total + int64(overhead)
+	return nil
+}
+
+// open 生成一次发送使用的请求体，长度未知时返回 -1
+func (m *multipartBody) open() (io.Reader, int64, error) {
+	if !m.stream {
+		return bytes.NewReader(m.buffered), m.size, nil
+	}
+
+	m.stop()
+	readers := make([]io.Reader, len(m.parts))
+	var closers []io.Closer
+	for i, part := range m.parts {
+		if part.Open != nil {
+			rc, err := part.Open()
+			if err != nil {
+				closeAll(closers)
+				return nil, 0, fmt.Errorf("open file %s: %w", part.FileName, err)
+			}
+			readers[i] = rc
+			closers = append(closers, rc)
+			continue
+		}
+		if _, err := part.Reader.(io.Seeker).Seek(m.starts[i], io.SeekStart); err != nil {
+			closeAll(closers)
+			return nil, 0, fmt.Errorf("rewind file %s: %w", part.FileName, err)
+		}
+		readers[i] = part.Reader
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := m.write(pw, readers)
+		closeAll(closers)
+		_ = pw.CloseWithError(err)
+	}()
+	m.pr, m.done = pr, done
+	return pr, m.size, nil
+}
+
+// stop 关闭上一次的请求体并等待写入协程退出，确保重放前不再读取文件
+func (m *multipartBody) stop() {
+	if m.pr == nil {
+		return
+	}
+	_ = m.pr.CloseWithError(errBodyReplaced)
+	<-m.done
+	m.pr, m.done = nil, nil
+}
+
+// write 写出完整的 multipart 载荷；readers 为 nil 时只写出结构不写文件内容
+func (m *multipartBody) write(w io.Writer, readers []io.Reader) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(m.boundary); err != nil {
+		return fmt.Errorf("set boundary: %w", err)
+	}
+
+	for i, part := range m.parts {
+		contentType := part.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(part.Field), quoteEscaper.Replace(part.FileName)))
+		header.Set("Content-Type", contentType)
+
+		pw, err := writer.CreatePart(header)
+		if err != nil {
+			return fmt.Errorf("create form file: %w", err)
+		}
+		if readers != nil {
+			if _, err := io.Copy(pw, readers[i]); err != nil {
+				return fmt.Errorf("copy file: %w", err)
+			}
+		}
+	}
+
+	for _, key := range m.keys {
+		if err := writer.WriteField(key, m.fields[key]); err != nil {
+			return fmt.Errorf("write field %s: %w", key, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("close writer: %w", err)
+	}
+	return nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+type countingWriter int64
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	*c += countingWriter(len(p))
+	return len(p), nil
+}
+
+func closeAll(closers []io.Closer) {
+	for _, c := range closers {
+		_ = c.Close()
+	}
+}
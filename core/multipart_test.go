@@ -0,0 +1,142 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// plainReader 隐藏底层类型，模拟不可重放的 Reader
+type plainReader struct{ io.Reader }
+
+func TestUploadMultipleParts(t *testing.T) {
+	type seen struct {
+		contentLength int64
+		chunked       bool
+		files         map[string]string
+		types         map[string]string
+		field         string
+	}
+	var got []seen
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := seen{
+			contentLength: r.ContentLength,
+			chunked:       len(r.TransferEncoding) > 0,
+			files:         map[string]string{},
+			types:         map[string]string{},
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse multipart: %v", err)
+			return
+		}
+		for field, headers := range r.MultipartForm.File {
+			f, _ := headers[0].Open()
+			data, _ := io.ReadAll(f)
+			f.Close()
+			s.files[field] = headers[0].Filename + ":" + string(data)
+			s.types[field] = headers[0].Header.Get("Content-Type")
+		}
+		s.field = r.FormValue("description")
+		got = append(got, s)
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, nil)
+	ctx := context.Background()
+
+	upload := func(parts ...FilePart) {
+		t.Helper()
+		b := client.Request().Path("/upload").UploadField("description", `{"title":"t"}`)
+		for _, p := range parts {
+			b.UploadPart(p)
+		}
+		if _, err := b.Post(ctx); err != nil {
+			t.Fatalf("upload: %v", err)
+		}
+	}
+
+	// 可 Seek 的 Reader：流式上传并携带 Content-Length
+	upload(
+		FilePart{Field: "media", FileName: "v.mp4", ContentType: "video/mp4", Reader: strings.NewReader("video")},
+		FilePart{Field: "thumb", FileName: "t.jpg", Reader: bytes.NewReader([]byte("thumb"))},
+	)
+	// 不可重放的 Reader：缓冲后发送
+	upload(FilePart{Field: "media", FileName: "a.jpg", Reader: plainReader{strings.NewReader("image")}})
+	// 大小未知的 Open：分块传输
+	upload(FilePart{Field: "media", FileName: "b.jpg", Open: func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("opened")), nil
+	}})
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 uploads, got %d", len(got))
+	}
+	first := got[0]
+	if first.files["media"] != "v.mp4:video" || first.files["thumb"] != "t.jpg:thumb" || first.field != `{"title":"t"}` {
+		t.Fatalf("unexpected parts: %+v", first)
+	}
+	if first.types["media"] != "video/mp4" || first.types["thumb"] != "application/octet-stream" {
+		t.Fatalf("unexpected content types: %v", first.types)
+	}
+	if first.contentLength <= 0 || first.chunked {
+		t.Fatalf("expected content length for known sizes, got %d chunked=%v", first.contentLength, first.chunked)
+	}
+	if got[1].files["media"] != "a.jpg:image" || got[1].contentLength <= 0 {
+		t.Fatalf("unexpected buffered upload: %+v", got[1])
+	}
+	if got[2].files["media"] != "b.jpg:opened" || !got[2].chunked {
+		t.Fatalf("expected chunked upload for unknown size: %+v", got[2])
+	}
+}
+
+func TestStreamingUploadReplays(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		f, _, err := r.FormFile("media")
+		if err != nil {
+			t.Errorf("form file: %v", err)
+			return
+		}
+		defer f.Close()
+		data, _ := io.ReadAll(f)
+		if string(data) != "payload" {
+			t.Errorf("attempt %d: upload not replayed from start: %q", n, data)
+		}
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "media_id": "m1"})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{BaseURL: server.URL, Retry: RetryPolicy{MaxAttempts: 2}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	// Reader 已读过前缀，重放时回到调用时的位置而不是文件开头
+	reader := strings.NewReader("skip:payload")
+	_, _ = reader.Seek(int64(len("skip:")), io.SeekStart)
+
+	type uploadResp struct {
+		MediaID string `json:"media_id"`
+	}
+	resp, err := NewTypedRequest[uploadResp](client).
+		Path("/cgi-bin/media/upload").
+		UploadFile("media", "a.jpg", reader).
+		Idempotent(true).
+		Post(context.Background())
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if resp.MediaID != "m1" || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("unexpected result: %+v after %d calls", resp, calls)
+	}
+}
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultRedisPoolSize    = 10
+	defaultRedisDialTimeout = 5 * time.Second
+	defaultRedisIOTimeout   = 3 * time.Second
+)
+
+const compareAndDeleteScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+var errRedisCacheClosed = errors.New("redis cache closed")
+
+// RedisCacheConfig Redis 缓存配置
+type RedisCacheConfig struct {
+	// Addr Redis 地址，格式 host:port
+	Addr string
+	// Username Redis 6+ ACL 用户名，为空时使用 AUTH password
+	Username string
+	// Password 密码，为空时不发送 AUTH
+	Password string
+	// DB 数据库编号，非 0 时建连后发送 SELECT
+	DB int
+	// KeyPrefix 所有 key 的统一前缀，便于多应用共用同一 Redis
+	KeyPrefix string
+	// TLSConfig 非 nil 时使用 TLS 连接
+	TLSConfig *tls.Config
+	// PoolSize 最大连接数，<= 0 时使用默认值 10
+	PoolSize int
+	// DialTimeout 建连超时，<= 0 时使用默认值 5s
+	DialTimeout time.Duration
+	// IOTimeout 单条命令读写超时，<= 0 时使用默认值 3s；ctx 截止时间更早时以 ctx 为准
+	IOTimeout time.Duration
+	// Logger 日志，Get 失败时记录告警
+	Logger *slog.Logger
+}
+
+// RedisCache 基于 Redis 的 Cache 实现
+// 内置最小化 RESP2 客户端与连接池，不依赖第三方 Redis 库，适用于多实例共享 access_token。
+type RedisCache struct {
+	cfg    RedisCacheConfig
+	logger *slog.Logger
+
+	sem    chan struct{}
+	idle   chan *respConn
+	closed atomic.Bool
+}
+
+// NewRedisCache 创建 Redis 缓存实例
+// 连接按需建立，创建时不会连接 Redis。
+func NewRedisCache(cfg RedisCacheConfig) (*RedisCache, error) {
+	if strings.TrimSpace(cfg.Addr) == "" {
+		return nil, fmt.Errorf("redis addr is required")
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = defaultRedisPoolSize
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaultRedisDialTimeout
+	}
+	if cfg.IOTimeout <= 0 {
+		cfg.IOTimeout = defaultRedisIOTimeout
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RedisCache{
+		cfg:    cfg,
+		logger: logger,
+		sem:    make(chan struct{}, cfg.PoolSize),
+		idle:   make(chan *respConn, cfg.PoolSize),
+	}, nil
+}
+
+// Get 获取缓存值，Redis 不可用时记录告警并视为未命中
+func (c *RedisCache) Get(ctx context.Context, key string) (string, bool) {
+	reply, err := c.do(ctx, "GET", c.key(key))
+	if err != nil {
+		if !errors.Is(err, errRespNil) {
+			c.logger.WarnContext(ctx, "redis get failed", slog.String("key", key), slog.Any("error", err))
+		}
+		return "", false
+	}
+
+	value, ok := reply.(string)
+	if !ok {
+		c.logger.WarnContext(ctx, "redis get unexpected reply", slog.String("key", key), slog.Any("reply", reply))
+		return "", false
+	}
+	return value, true
+}
+
+// Set 设置缓存值，ttl 为 0 时永不过期
+func (c *RedisCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	args := []string{"SET", c.key(key), value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	}
+	if _, err := c.do(ctx, args...); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除缓存
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if _, err := c.do(ctx, "DEL", c.key(key)); err != nil {
+		return fmt.Errorf("redis del: %w", err)
+	}
+	return nil
+}
+
+// SetNX 仅当 key 不存在时设置缓存值（SET NX PX）
+func (c *RedisCache) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	args := []string{"SET", c.key(key), value, "NX"}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	}
+	if _, err := c.do(ctx, args...); err != nil {
+		if errors.Is(err, errRespNil) {
+			return false, nil
+		}
+		return false, fmt.Errorf("redis setnx: %w", err)
+	}
+	return true, nil
+}
+
+// CompareAndDelete 仅当缓存值等于 value 时删除（Lua 脚本保证原子性）
+func (c *RedisCache) CompareAndDelete(ctx context.Context, key string, value string) (bool, error) {
+	reply, err := c.do(ctx, "EVAL", compareAndDeleteScript, "1", c.key(key), value)
+	if err != nil {
+		return false, fmt.Errorf("redis compare and delete: %w", err)
+	}
+	n, _ := reply.(int64)
+	return n == 1, nil
+}
+
+// TTL 返回缓存值的剩余生存时间（PTTL），永不过期时返回 0；Redis 不可用时记录告警并视为不存在
+func (c *RedisCache) TTL(ctx context.Context, key string) (time.Duration, bool) {
+	reply, err := c.do(ctx, "PTTL", c.key(key))
+	if err != nil {
+		c.logger.WarnContext(ctx, "redis pttl failed", slog.String("key", key), slog.Any("error", err))
+		return 0, false
+	}
+
+	// -2 表示 key 不存在，-1 表示永不过期
+	ms, ok := reply.(int64)
+	switch {
+	case !ok || ms == -2:
+		return 0, false
+	case ms == -1:
+		return 0, true
+	default:
+		return time.Duration(ms) * time.Millisecond, true
+	}
+}
+
+// Close 关闭所有空闲连接，正在使用的连接归还时会被关闭
+func (c *RedisCache) Close() error {
+	c.closed.Store(true)
+
+	var errs []error
+	for {
+		select {
+		case conn := <-c.idle:
+			if err := conn.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
+
+func (c *RedisCache) key(key string) string {
+	return c.cfg.KeyPrefix + key
+}
+
+func (c *RedisCache) do(ctx context.Context, args ...string) (any, error) {
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(conn)
+
+	return conn.do(ctx, args...)
+}
+
+func (c *RedisCache) acquire(ctx context.Context) (*respConn, error) {
+	if c.closed.Load() {
+		return nil, errRedisCacheClosed
+	}
+
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		<-c.sem
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *RedisCache) release(conn *respConn) {
+	defer func() { <-c.sem }()
+
+	if conn.broken || c.closed.Load() {
+		_ = conn.Close()
+		return
+	}
+	select {
+	case c.idle <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
+
+func (c *RedisCache) dial(ctx context.Context) (*respConn, error) {
+	var netConn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: c.cfg.DialTimeout}
+	if c.cfg.TLSConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: c.cfg.TLSConfig}
+		netConn, err = tlsDialer.DialContext(ctx, "tcp", c.cfg.Addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.cfg.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial redis: %w", err)
+	}
+
+	conn := newRespConn(netConn, c.cfg.IOTimeout)
+	if c.cfg.Password != "" {
+		args := []string{"AUTH", c.cfg.Password}
+		if c.cfg.Username != "" {
+			args = []string{"AUTH", c.cfg.Username, c.cfg.Password}
+		}
+		if _, err := conn.do(ctx, args...); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if c.cfg.DB != 0 {
+		if _, err := conn.do(ctx, "SELECT", strconv.Itoa(c.cfg.DB)); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis select: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// 确保 RedisCache 实现了 Cache 与 AtomicCache 接口
+var (
+	_ Cache       = (*RedisCache)(nil)
+	_ AtomicCache = (*RedisCache)(nil)
+	_ TTLCache    = (*RedisCache)(nil)
+)
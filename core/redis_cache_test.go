@@ -0,0 +1,388 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRedisEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// fakeRedisServer 进程内 RESP2 服务端，支持 AUTH/SELECT/GET/SET/PTTL/DEL/EVAL（仅解锁脚本）。
+type fakeRedisServer struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[int]map[string]fakeRedisEntry
+	commands []string
+	dials    atomic.Int32
+}
+
+func newFakeRedisServer(t *testing.T, password string) *fakeRedisServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeRedisServer{ln: ln, password: password, data: make(map[int]map[string]fakeRedisEntry)}
+	go s.serve()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+func (s *fakeRedisServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *fakeRedisServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.dials.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedisServer) handle(conn net.Conn) {
+	defer conn.Close()
+	rc := newRespConn(conn, time.Minute)
+	authed := s.password == ""
+	db := 0
+	for {
+		req, err := rc.readReply()
+		if err != nil {
+			return
+		}
+		items, _ := req.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		s.mu.Unlock()
+
+		var reply string
+		switch {
+		case cmd == "AUTH":
+			if args[len(args)-1] == s.password {
+				authed = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case cmd == "SELECT":
+			db, _ = strconv.Atoi(args[1])
+			reply = "+OK\r\n"
+		default:
+			reply = s.exec(db, cmd, args[1:])
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedisServer) exec(db int, cmd string, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[db] == nil {
+		s.data[db] = make(map[string]fakeRedisEntry)
+	}
+	data := s.data[db]
+	lookup := func(key string) (fakeRedisEntry, bool) {
+		entry, ok := data[key]
+		if ok && !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+			delete(data, key)
+			return fakeRedisEntry{}, false
+		}
+		return entry, ok
+	}
+
+	switch cmd {
+	case "GET":
+		entry, ok := lookup(args[0])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(entry.value), entry.value)
+	case "SET":
+		entry := fakeRedisEntry{value: args[1]}
+		nx := false
+		for i := 2; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "PX":
+				ms, _ := strconv.Atoi(args[i+1])
+				entry.expiresAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+				i++
+			case "NX":
+				nx = true
+			}
+		}
+		if _, exists := lookup(args[0]); exists && nx {
+			return "$-1\r\n"
+		}
+		data[args[0]] = entry
+		return "+OK\r\n"
+	case "PTTL":
+		entry, ok := lookup(args[0])
+		switch {
+		case !ok:
+			return ":-2\r\n"
+		case entry.expiresAt.IsZero():
+			return ":-1\r\n"
+		default:
+			return fmt.Sprintf(":%d\r\n", time.Until(entry.expiresAt).Milliseconds())
+		}
+	case "DEL":
+		n := 0
+		for _, key := range args {
+			if _, ok := lookup(key); ok {
+				delete(data, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "EVAL":
+		// 仅支持 compareAndDeleteScript：EVAL script 1 key value
+		entry, ok := lookup(args[2])
+		if !ok || entry.value != args[3] {
+			return ":0\r\n"
+		}
+		delete(data, args[2])
+		return ":1\r\n"
+	default:
+		return "-ERR unknown command '" + cmd + "'\r\n"
+	}
+}
+
+func (s *fakeRedisServer) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+func TestRedisCache_SetGetDelete(t *testing.T) {
+	server := newFakeRedisServer(t, "")
+	cache, err := NewRedisCache(RedisCacheConfig{Addr: server.Addr(), KeyPrefix: "app:"})
+	require.NoError(t, err)
+	defer cache.Close()
+	ctx := context.Background()
+
+	_, ok := cache.Get(ctx, "missing")
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Set(ctx, "token", "value-1", time.Hour))
+	got, ok := cache.Get(ctx, "token")
+	assert.True(t, ok)
+	assert.Equal(t, "value-1", got)
+
+	require.NoError(t, cache.Set(ctx, "empty", "", 0))
+	got, ok = cache.Get(ctx, "empty")
+	assert.True(t, ok)
+	assert.Empty(t, got)
+
+	require.NoError(t, cache.Delete(ctx, "token"))
+	require.NoError(t, cache.Delete(ctx, "token"))
+	_, ok = cache.Get(ctx, "token")
+	assert.False(t, ok)
+
+	assert.Contains(t, server.Commands(), "SET app:token value-1 PX 3600000")
+	assert.Contains(t, server.Commands(), "SET app:empty ")
+}
+
+func TestRedisCache_Expiration(t *testing.T) {
+	server := newFakeRedisServer(t, "")
+	cache, err := NewRedisCache(RedisCacheConfig{Addr: server.Addr()})
+	require.NoError(t, err)
+	defer cache.Close()
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, "temp", "value", 10*time.Millisecond))
+	time.Sleep(30 * time.Millisecond)
+
+	_, ok := cache.Get(ctx, "temp")
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Set(ctx, "hour", "value", time.Hour))
+	require.NoError(t, cache.Set(ctx, "forever", "value", 0))
+	ttl, ok := cache.TTL(ctx, "hour")
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour.Seconds(), ttl.Seconds(), 1)
+	ttl, ok = cache.TTL(ctx, "forever")
+	assert.True(t, ok)
+	assert.Zero(t, ttl)
+	_, ok = cache.TTL(ctx, "temp")
+	assert.False(t, ok)
+}
+
+func TestRedisCache_AuthAndSelect(t *testing.T) {
+	server := newFakeRedisServer(t, "pass")
+	ctx := context.Background()
+
+	cache, err := NewRedisCache(RedisCacheConfig{Addr: server.Addr(), Username: "user", Password: "pass", DB: 2})
+	require.NoError(t, err)
+	defer cache.Close()
+
+	require.NoError(t, cache.Set(ctx, "k", "v", 0))
+	got, ok := cache.Get(ctx, "k")
+	assert.True(t, ok)
+	assert.Equal(t, "v", got)
+
+	commands := server.Commands()
+	require.GreaterOrEqual(t, len(commands), 2)
+	assert.Equal(t, "AUTH user pass", commands[0])
+	assert.Equal(t, "SELECT 2", commands[1])
+
+	server.mu.Lock()
+	_, inDB2 := server.data[2]["k"]
+	server.mu.Unlock()
+	assert.True(t, inDB2)
+
+	bad, err := NewRedisCache(RedisCacheConfig{Addr: server.Addr(), Password: "wrong"})
+	require.NoError(t, err)
+	defer bad.Close()
+	err = bad.Set(ctx, "k", "v", 0)
+	var respErr RespError
+	assert.ErrorAs(t, err, &respErr)
+}
+
+func TestRedisCache_PoolReusesConnections(t *testing.T) {
+	server := newFakeRedisServer(t, "")
+	cache, err := NewRedisCache(RedisCacheConfig{Addr: server.Addr(), PoolSize: 2})
+	require.NoError(t, err)
+	defer cache.Close()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() {
+			key := "k" + strconv.Itoa(i)
+			assert.NoError(t, cache.Set(ctx, key, key, time.Minute))
+			got, ok := cache.Get(ctx, key)
+			assert.True(t, ok)
+			assert.Equal(t, key, got)
+		})
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, server.dials.Load(), int32(2))
+}
+
+func TestRedisCache_Unavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	cache, err := NewRedisCache(RedisCacheConfig{Addr: addr, DialTimeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	_, ok := cache.Get(context.Background(), "k")
+	assert.False(t, ok)
+	assert.Error(t, cache.Set(context.Background(), "k", "v", 0))
+
+	_, err = NewRedisCache(RedisCacheConfig{})
+	assert.Error(t, err)
+}
+
+func TestRedisCache_SetNXAndCompareAndDelete(t *testing.T) {
+	server := newFakeRedisServer(t, "")
+	cache, err := NewRedisCache(RedisCacheConfig{Addr: server.Addr()})
+	require.NoError(t, err)
+	defer cache.Close()
+	ctx := context.Background()
+
+	ok, err := cache.SetNX(ctx, "lock", "a", time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = cache.SetNX(ctx, "lock", "b", time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = cache.CompareAndDelete(ctx, "lock", "b")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = cache.CompareAndDelete(ctx, "lock", "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, exists := cache.Get(ctx, "lock")
+	assert.False(t, exists)
+}
+
+func TestRespConn_ArrayWithErrorElementIsFullyRead(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go func() {
+		srv := newRespConn(server, time.Minute)
+		for _, reply := range []string{"*3\r\n+OK\r\n-ERR inner failure\r\n:1\r\n", "+PONG\r\n"} {
+			if _, err := srv.readReply(); err != nil {
+				return
+			}
+			if _, err := server.Write([]byte(reply)); err != nil {
+				return
+			}
+		}
+	}()
+
+	rc := newRespConn(client, time.Second)
+	_, err := rc.do(context.Background(), "EXEC")
+	var respErr RespError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, RespError("ERR inner failure"), respErr)
+	assert.False(t, rc.broken)
+
+	// 数组剩余元素已读完，下一条命令读到的是自己的回复
+	reply, err := rc.do(context.Background(), "PING")
+	require.NoError(t, err)
+	assert.Equal(t, "PONG", reply)
+}
+
+func TestRespConn_CancelInterruptsRead(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go func() {
+		// 读取命令后不回复，模拟 Redis 阻塞
+		srv := newRespConn(server, time.Minute)
+		_, _ = srv.readReply()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	rc := newRespConn(client, time.Minute)
+	start := time.Now()
+	_, err := rc.do(ctx, "GET", "k")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, rc.broken)
+}
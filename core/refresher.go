@@ -0,0 +1,159 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRefreshAhead      = time.Minute
+	defaultRefreshMinBackoff = time.Second
+	defaultRefreshMaxBackoff = 5 * time.Minute
+	minRefreshInterval       = time.Second
+)
+
+// RefreshTask 后台刷新任务
+// ahead 为距过期多久开始续期，返回距下一次执行的等待时长。
+type RefreshTask func(ctx context.Context, ahead time.Duration) (time.Duration, error)
+
+// RefresherConfig 后台刷新器配置
+type RefresherConfig struct {
+	// Ahead 在缓存过期前多久主动续期，<= 0 时使用默认值 1 分钟
+	Ahead time.Duration
+	// MinBackoff 刷新失败后的首次重试等待，<= 0 时使用默认值 1s
+	MinBackoff time.Duration
+	// MaxBackoff 刷新失败后的最长重试等待，<= 0 时使用默认值 5min
+	MaxBackoff time.Duration
+	// OnError 刷新失败回调，name 为任务名
+	OnError func(name string, err error)
+	// Logger 日志
+	Logger *slog.Logger
+}
+
+type namedRefreshTask struct {
+	name string
+	task RefreshTask
+}
+
+// Refresher 后台刷新器
+// 定期在 token / ticket 过期前续期，使请求路径始终命中缓存，不必同步等待上游获取。
+type Refresher struct {
+	ahead      time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	onError    func(name string, err error)
+	logger     *slog.Logger
+
+	mu     sync.Mutex
+	tasks  []namedRefreshTask
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRefresher 创建后台刷新器，需调用 Start 启动
+func NewRefresher(cfg RefresherConfig) *Refresher {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	minBackoff := cmp.Or(max(cfg.MinBackoff, 0), defaultRefreshMinBackoff)
+	return &Refresher{
+		ahead:      cmp.Or(max(cfg.Ahead, 0), defaultRefreshAhead),
+		minBackoff: minBackoff,
+		maxBackoff: max(cmp.Or(max(cfg.MaxBackoff, 0), defaultRefreshMaxBackoff), minBackoff),
+		onError:    cfg.OnError,
+		logger:     logger,
+	}
+}
+
+// Add 注册刷新任务，需在 Start 之前调用
+func (r *Refresher) Add(name string, task RefreshTask) *Refresher {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tasks = append(r.tasks, namedRefreshTask{name: name, task: task})
+	return r
+}
+
+// Start 为每个任务启动后台 goroutine，ctx 结束或调用 Stop 时退出
+func (r *Refresher) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return fmt.Errorf("refresher already started")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	for _, t := range r.tasks {
+		r.wg.Go(func() {
+			r.run(ctx, t)
+		})
+	}
+	return nil
+}
+
+// Stop 停止所有任务并等待其退出，可重复调用
+func (r *Refresher) Stop() {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.cancel = nil
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	r.wg.Wait()
+}
+
+func (r *Refresher) run(ctx context.Context, t namedRefreshTask) {
+	failures := 0
+	for {
+		next, err := t.task(ctx, r.ahead)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			failures++
+			next = r.backoff(failures)
+			r.logger.WarnContext(ctx, "background refresh failed",
+				slog.String("task", t.name),
+				slog.Int("failures", failures),
+				slog.Duration("retry_in", next),
+				slog.Any("error", err),
+			)
+			if r.onError != nil {
+				r.onError(t.name, err)
+			}
+		} else {
+			failures = 0
+			next = max(next, minRefreshInterval)
+			r.logger.DebugContext(ctx, "background refresh scheduled", slog.String("task", t.name), slog.Duration("next", next))
+		}
+
+		timer := time.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *Refresher) backoff(failures int) time.Duration {
+	wait := r.minBackoff
+	for range failures - 1 {
+		wait *= 2
+		if wait >= r.maxBackoff {
+			return r.maxBackoff
+		}
+	}
+	return wait
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresherSchedulesAndBacksOff(t *testing.T) {
+	var calls atomic.Int32
+	var failures atomic.Int32
+	errFetch := errors.New("fetch failed")
+
+	r := NewRefresher(RefresherConfig{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 2 * time.Millisecond,
+		OnError: func(name string, err error) {
+			assert.Equal(t, "token", name)
+			assert.ErrorIs(t, err, errFetch)
+			failures.Add(1)
+		},
+	})
+	r.Add("token", func(ctx context.Context, ahead time.Duration) (time.Duration, error) {
+		assert.Equal(t, defaultRefreshAhead, ahead)
+		if calls.Add(1) <= 2 {
+			return 0, errFetch
+		}
+		return time.Hour, nil
+	})
+
+	require.NoError(t, r.Start(context.Background()))
+	assert.Error(t, r.Start(context.Background()), "second start should fail")
+
+	require.Eventually(t, func() bool { return calls.Load() == 3 }, time.Second, time.Millisecond)
+	r.Stop()
+	r.Stop()
+
+	assert.Equal(t, int32(2), failures.Load())
+	assert.Equal(t, int32(3), calls.Load(), "task should wait for the returned delay")
+}
+
+func TestRefresherBackoff(t *testing.T) {
+	r := NewRefresher(RefresherConfig{MinBackoff: time.Second, MaxBackoff: 5 * time.Second})
+	assert.Equal(t, time.Second, r.backoff(1))
+	assert.Equal(t, 2*time.Second, r.backoff(2))
+	assert.Equal(t, 4*time.Second, r.backoff(3))
+	assert.Equal(t, 5*time.Second, r.backoff(4))
+}
+
+func TestTokenManagerRenew(t *testing.T) {
+	cache := NewMemoryCache()
+	var calls atomic.Int32
+	expiresIn := 7200
+
+	m, err := NewTokenManager(TokenManagerConfig{
+		Cache:               cache,
+		CacheKey:            "token-key",
+		ExpireBufferSeconds: 300,
+		Fetcher: func(ctx context.Context) (TokenFetchResult, error) {
+			calls.Add(1)
+			return TokenFetchResult{Token: "fresh", ExpiresIn: expiresIn}, nil
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	next, err := m.Renew(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.InDelta(t, (6900*time.Second - time.Minute).Seconds(), next.Seconds(), 2)
+
+	token, err := m.GetToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", token)
+
+	_, err = m.Renew(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "token far from expiry should not be renewed")
+
+	_, err = m.Renew(ctx, 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "token within ahead window should be renewed")
+}
+
+func TestTokenManagerRenewTokenCachedBeforeUpgrade(t *testing.T) {
+	cache := NewMemoryCache()
+	ctx := context.Background()
+	// 升级前写入的 token 没有 :expires_at 记录
+	require.NoError(t, cache.Set(ctx, "token-key", "legacy", time.Hour))
+
+	var calls atomic.Int32
+	newManager := func(cache Cache) *TokenManager {
+		m, err := NewTokenManager(TokenManagerConfig{
+			Cache:    cache,
+			CacheKey: "token-key",
+			Fetcher: func(ctx context.Context) (TokenFetchResult, error) {
+				calls.Add(1)
+				return TokenFetchResult{Token: "fresh", ExpiresIn: 7200}, nil
+			},
+		})
+		require.NoError(t, err)
+		return m
+	}
+
+	next, err := newManager(cache).Renew(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), calls.Load(), "legacy token should not be force refreshed")
+	assert.InDelta(t, (time.Hour - time.Minute).Seconds(), next.Seconds(), 2)
+
+	// 不支持 TTL 查询的缓存无法得知有效期，稍后重新检查
+	next, err = newManager(struct{ Cache }{cache}).Renew(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), calls.Load())
+	assert.Equal(t, time.Minute, next)
+
+	// 剩余有效期不足 ahead 时仍会续期
+	next, err = newManager(cache).Renew(ctx, 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 2*time.Hour, next)
+}
+
+func TestTokenManagerRenewSkipsWhenLockHeld(t *testing.T) {
+	cache := NewMemoryCache()
+	locker := NewMemoryLocker()
+	_, _ = locker.TryLock(context.Background(), "token-key"+lockKeySuffix, time.Minute)
+
+	m, err := NewTokenManager(TokenManagerConfig{
+		Cache:    cache,
+		CacheKey: "token-key",
+		Fetcher: func(ctx context.Context) (TokenFetchResult, error) {
+			t.Error("fetcher should not be called while lock is held")
+			return TokenFetchResult{}, nil
+		},
+		Locker:           locker,
+		LockPollInterval: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	next, err := m.Renew(context.Background(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 50*time.Millisecond, next)
+}
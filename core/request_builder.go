@@ -6,14 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
-	"mime/multipart"
 	"net/http"
+	"time"
 )
 
 type RawResponse struct {
 	StatusCode int
+	Header     http.Header
 	Body       []byte
+
+	// stream 流式模式下未读取的响应体，Body 为空
+	stream io.ReadCloser
 }
 
 type RequestBuilder struct {
@@ -22,10 +27,11 @@ type RequestBuilder struct {
 	query      map[string]string
 	body       any
 	withToken  bool
-	uploadFile io.Reader
-	uploadName string
-	uploadPart string
+	files      []FilePart
 	formFields map[string]string
+	idempotent *bool
+	maxBody    int64
+	stream     bool
 }
 
 func newRequestBuilder(client *Client) *RequestBuilder {
@@ -69,10 +75,21 @@ func (b *RequestBuilder) WithoutToken() *RequestBuilder {
 	return b
 }
 
+// Idempotent 声明请求是否幂等，幂等请求遇到瞬时错误时会按 RetryPolicy 重试。
+// 未声明时 GET 视为幂等，POST 视为非幂等。
+func (b *RequestBuilder) Idempotent(idempotent bool) *RequestBuilder {
+	b.idempotent = &idempotent
+	return b
+}
+
+// UploadFile 添加一个上传文件，多次调用可上传多个文件
 func (b *RequestBuilder) UploadFile(field, fileName string, r io.Reader) *RequestBuilder {
-	b.uploadPart = field
-	b.uploadName = fileName
-	b.uploadFile = r
+	return b.UploadPart(FilePart{Field: field, FileName: fileName, Reader: r})
+}
+
+// UploadPart 添加一个上传文件，可指定 Content-Type、大小或可重复打开的数据源
+func (b *RequestBuilder) UploadPart(part FilePart) *RequestBuilder {
+	b.files = append(b.files, part)
 	return b
 }
 
@@ -84,136 +101,253 @@ func (b *RequestBuilder) UploadField(key, value string) *RequestBuilder {
 	return b
 }
 
+// MaxBodySize 限制响应体大小，超出时返回 ErrResponseTooLarge；<= 0 表示不限制。
+func (b *RequestBuilder) MaxBodySize(n int64) *RequestBuilder {
+	b.maxBody = n
+	return b
+}
+
 func (b *RequestBuilder) Get(ctx context.Context) (RawResponse, error) {
 	return b.execute(ctx, http.MethodGet)
 }
 
 func (b *RequestBuilder) Post(ctx context.Context) (RawResponse, error) {
-	if b.uploadFile != nil {
+	if len(b.files) > 0 {
 		return b.executeUpload(ctx)
 	}
 	return b.execute(ctx, http.MethodPost)
 }
 
-func (b *RequestBuilder) buildQuery(ctx context.Context) (map[string]string, error) {
+func (b *RequestBuilder) isIdempotent(method string) bool {
+	if b.idempotent != nil {
+		return *b.idempotent
+	}
+	return method == http.MethodGet
+}
+
+func (b *RequestBuilder) accessToken(ctx context.Context) (string, error) {
 	if !b.withToken || b.client.tokenProvider == nil {
-		return b.query, nil
+		return "", nil
 	}
 
 	token, err := b.client.tokenProvider.GetToken(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get access token: %w", err)
+		return "", fmt.Errorf("get access token: %w", err)
 	}
-
-	params := make(map[string]string, len(b.query)+1)
-	if len(b.query) > 0 {
-		maps.Copy(params, b.query)
-	}
-	params["access_token"] = token
-	return params, nil
+	return token, nil
 }
 
-func (b *RequestBuilder) execute(ctx context.Context, method string) (RawResponse, error) {
-	var zero RawResponse
+// renewToken 在 access_token 失效后获取新 token。
+// 若其他请求已完成刷新（缓存中的 token 已变化）则直接复用，否则走 RefreshToken 的单飞刷新。
+func (b *RequestBuilder) renewToken(ctx context.Context, stale string) (string, error) {
+	if token, err := b.client.tokenProvider.GetToken(ctx); err == nil && token != "" && token != stale {
+		return token, nil
+	}
 
-	query, err := b.buildQuery(ctx)
+	token, err := b.client.tokenProvider.RefreshToken(ctx)
 	if err != nil {
-		return zero, err
+		return "", fmt.Errorf("refresh access token: %w", err)
 	}
+	return token, nil
+}
 
-	rawURL, err := b.client.buildURL(b.path, query)
-	if err != nil {
-		return zero, fmt.Errorf("build url: %w", err)
+func (b *RequestBuilder) buildQuery(token string) map[string]string {
+	params := make(map[string]string, len(b.query)+1)
+	if len(b.query) > 0 {
+		maps.Copy(params, b.query)
 	}
+	if token != "" {
+		params["access_token"] = token
+	}
+	return params
+}
 
+func (b *RequestBuilder) execute(ctx context.Context, method string) (RawResponse, error) {
 	var reqBody []byte
-	var bodyReader io.Reader
+	var contentType string
 	if b.body != nil {
+		var err error
 		reqBody, err = json.Marshal(b.body)
 		if err != nil {
-			return zero, fmt.Errorf("marshal body: %w", err)
+			return RawResponse{}, fmt.Errorf("marshal body: %w", err)
 		}
-		bodyReader = bytes.NewReader(reqBody)
+		contentType = "application/json"
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, rawURL, bodyReader)
+	return b.send(ctx, method, bytesBody(reqBody), contentType, reqBody)
+}
+
+func (b *RequestBuilder) executeUpload(ctx context.Context) (RawResponse, error) {
+	payload, err := newMultipartBody(b.files, b.formFields)
 	if err != nil {
-		return zero, fmt.Errorf("create request: %w", err)
-	}
-	if len(reqBody) > 0 {
-		req.Header.Set("Content-Type", "application/json")
+		return RawResponse{}, err
 	}
+	defer payload.stop()
 
-	b.client.logRequest(ctx, method, rawURL, reqBody)
+	// 每次发送尝试重新生成载荷，token 失效重放或重试时从头发送。
+	return b.send(ctx, http.MethodPost, payload.open, payload.contentType(), nil)
+}
 
-	resp, err := b.client.httpClient.Do(req)
-	if err != nil {
-		return zero, fmt.Errorf("do request: %w", err)
-	}
-	defer resp.Body.Close()
+// bodyFunc 为每次发送尝试生成请求体及其长度（未知时为 -1）
+type bodyFunc func() (io.Reader, int64, error)
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return zero, fmt.Errorf("read response: %w", err)
+func bytesBody(data []byte) bodyFunc {
+	if data == nil {
+		return nil
+	}
+	return func() (io.Reader, int64, error) {
+		return bytes.NewReader(data), int64(len(data)), nil
 	}
-
-	b.client.logResponse(ctx, resp.StatusCode, respBody)
-	return RawResponse{StatusCode: resp.StatusCode, Body: respBody}, nil
 }
 
-func (b *RequestBuilder) executeUpload(ctx context.Context) (RawResponse, error) {
+// send 发送请求；响应为 access_token 失效错误时按 TokenRetryPolicy 刷新 token 并重放请求，
+// 幂等请求遇到瞬时错误时按 RetryPolicy 退避重试。
+// jsonBody 为可对外暴露（日志、中间件）的 JSON 请求体，multipart 上传时为 nil。
+func (b *RequestBuilder) send(ctx context.Context, method string, body bodyFunc, contentType string, jsonBody []byte) (RawResponse, error) {
 	var zero RawResponse
 
-	query, err := b.buildQuery(ctx)
+	token, err := b.accessToken(ctx)
 	if err != nil {
 		return zero, err
 	}
 
-	rawURL, err := b.client.buildURL(b.path, query)
-	if err != nil {
-		return zero, fmt.Errorf("build url: %w", err)
+	policy := b.client.retryPolicy
+	canRetry := b.isIdempotent(method)
+	tokenRetries, transientRetries := 0, 0
+	for attempt := 1; ; attempt++ {
+		resp, wechatErr, err := b.do(ctx, method, token, attempt, body, contentType, jsonBody)
+		if err == nil && token != "" && tokenRetries < b.client.tokenRetries && wechatErr != nil && isTokenErrCode(wechatErr.ErrCode) {
+			tokenRetries++
+			b.client.logger.InfoContext(ctx, "access token rejected, refreshing",
+				slog.String("path", b.path),
+				slog.Any("error", wechatErr),
+			)
+			token, err = b.renewToken(ctx, token)
+			if err != nil {
+				return zero, err
+			}
+			continue
+		}
+
+		if canRetry && transientRetries+1 < policy.MaxAttempts {
+			if reason, ok := policy.retryable(resp, wechatErr, err); ok {
+				transientRetries++
+				wait := policy.backoff(transientRetries)
+				attrs := []any{
+					slog.String("path", b.path),
+					slog.Int("attempt", attempt),
+					slog.String("reason", reason),
+					slog.Int("status", resp.StatusCode),
+					slog.Duration("backoff", wait),
+				}
+				if wechatErr != nil {
+					attrs = append(attrs, slog.Int("errcode", wechatErr.ErrCode))
+				}
+				if err != nil {
+					attrs = append(attrs, slog.Any("error", err))
+				}
+				b.client.logger.WarnContext(ctx, "retrying request", attrs...)
+				if sleepContext(ctx, wait) == nil {
+					resp.closeStream()
+					continue
+				}
+			}
+		}
+
+		if err != nil {
+			return zero, err
+		}
+		return resp, nil
 	}
+}
 
-	payload := &bytes.Buffer{}
-	writer := multipart.NewWriter(payload)
+// do 经过中间件链发送一次 HTTP 请求。
+func (b *RequestBuilder) do(ctx context.Context, method, token string, attempt int, body bodyFunc, contentType string, jsonBody []byte) (RawResponse, *WechatError, error) {
+	req := &MiddlewareRequest{
+		Method:  method,
+		Path:    b.path,
+		Query:   b.buildQuery(token),
+		Header:  make(http.Header),
+		Body:    jsonBody,
+		Attempt: attempt,
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
-	part, err := writer.CreateFormFile(b.uploadPart, b.uploadName)
+	middlewares := b.client.middlewares
+	ctx, entered, err := runRequestMiddlewares(ctx, middlewares, req)
 	if err != nil {
-		return zero, fmt.Errorf("create form file: %w", err)
+		resp := &MiddlewareResponse{Err: err}
+		runResponseMiddlewares(middlewares, entered, req, resp)
+		return RawResponse{}, nil, resp.Err
 	}
-	if _, err = io.Copy(part, b.uploadFile); err != nil {
-		return zero, fmt.Errorf("copy file: %w", err)
+
+	start := time.Now()
+	raw, err := b.roundTrip(ctx, req, body)
+	resp := &MiddlewareResponse{Raw: raw, Err: err, Duration: time.Since(start)}
+	if err == nil && IsJSONResponse(raw.Header, raw.Body) {
+		resp.WechatError = parseWechatError(raw.Body)
 	}
 
-	for key, value := range b.formFields {
-		if err = writer.WriteField(key, value); err != nil {
-			return zero, fmt.Errorf("write field %s: %w", key, err)
+	runResponseMiddlewares(middlewares, entered, req, resp)
+	if resp.Err != nil {
+		raw.closeStream()
+		return RawResponse{}, nil, resp.Err
+	}
+	// 中间件改写了响应体（如故障注入）时按新响应体重新解析，避免旧的 WechatError 影响 token 刷新与重试判断
+	if !bytes.Equal(resp.Raw.Body, raw.Body) {
+		resp.WechatError = nil
+		if IsJSONResponse(resp.Raw.Header, resp.Raw.Body) {
+			resp.WechatError = parseWechatError(resp.Raw.Body)
 		}
 	}
+	return resp.Raw, resp.WechatError, nil
+}
+
+func (b *RequestBuilder) roundTrip(ctx context.Context, mreq *MiddlewareRequest, body bodyFunc) (RawResponse, error) {
+	var zero RawResponse
 
-	if err = writer.Close(); err != nil {
-		return zero, fmt.Errorf("close writer: %w", err)
+	rawURL, err := b.client.buildURL(mreq.Path, mreq.Query)
+	if err != nil {
+		return zero, fmt.Errorf("build url: %w", err)
+	}
+
+	var bodyReader io.Reader
+	contentLength := int64(0)
+	if body != nil {
+		bodyReader, contentLength, err = body()
+		if err != nil {
+			return zero, err
+		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, payload)
+	req, err := http.NewRequestWithContext(ctx, mreq.Method, rawURL, bodyReader)
 	if err != nil {
 		return zero, fmt.Errorf("create request: %w", err)
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header = mreq.Header
+	if bodyReader != nil {
+		req.ContentLength = contentLength
+	}
 
-	b.client.logRequest(ctx, http.MethodPost, rawURL, nil)
+	b.client.logRequest(ctx, mreq.Method, rawURL, mreq.Body)
 
 	resp, err := b.client.httpClient.Do(req)
 	if err != nil {
-		return zero, fmt.Errorf("do request: %w", err)
+		return zero, &transportError{fmt.Errorf("do request: %w", err)}
+	}
+	if b.stream {
+		return b.openStream(ctx, resp)
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := readBody(resp.Body, b.maxBody)
 	if err != nil {
-		return zero, fmt.Errorf("read response: %w", err)
+		return zero, &transportError{err}
 	}
 
-	b.client.logResponse(ctx, resp.StatusCode, respBody)
-	return RawResponse{StatusCode: resp.StatusCode, Body: respBody}, nil
+	raw := RawResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}
+	b.client.logResponse(ctx, raw)
+	return raw, nil
 }
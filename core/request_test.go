@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -145,3 +147,133 @@ func TestTypedRequestUpload(t *testing.T) {
 		t.Fatalf("unexpected media id: %s", resp.MediaID)
 	}
 }
+
+type rotatingTokenProvider struct {
+	mu        sync.Mutex
+	token     string
+	next      string
+	refreshes int
+}
+
+func (p *rotatingTokenProvider) GetToken(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.token, nil
+}
+
+func (p *rotatingTokenProvider) RefreshToken(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refreshes++
+	p.token = p.next
+	return p.token, nil
+}
+
+func TestRequestRefreshesTokenOnTokenError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("access_token") != "new" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeInvalidToken, "errmsg": "invalid credential"})
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["touser"] != "o1" {
+			t.Errorf("body not replayed: %v", body)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "msgid": 1})
+	}))
+	defer server.Close()
+
+	provider := &rotatingTokenProvider{token: "old", next: "new"}
+	client := newTestClient(t, server, provider)
+
+	type resp struct {
+		MsgID int `json:"msgid"`
+	}
+	got, err := NewTypedRequest[resp](client).
+		Path("/cgi-bin/message/custom/send").
+		Body(map[string]string{"touser": "o1"}).
+		Post(context.Background())
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if got.MsgID != 1 {
+		t.Fatalf("unexpected msgid: %d", got.MsgID)
+	}
+	if provider.refreshes != 1 {
+		t.Fatalf("expected 1 refresh, got %d", provider.refreshes)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestRequestRefreshesTokenOnUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "new" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeExpiredToken, "errmsg": "access_token expired"})
+			return
+		}
+		f, _, err := r.FormFile("media")
+		if err != nil {
+			t.Errorf("form file: %v", err)
+			return
+		}
+		defer f.Close()
+		data, _ := io.ReadAll(f)
+		if string(data) != "hello" {
+			t.Errorf("upload not replayed: %q", data)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "media_id": "m1"})
+	}))
+	defer server.Close()
+
+	provider := &rotatingTokenProvider{token: "old", next: "new"}
+	client := newTestClient(t, server, provider)
+
+	type uploadResp struct {
+		MediaID string `json:"media_id"`
+	}
+	got, err := NewTypedRequest[uploadResp](client).
+		Path("/cgi-bin/media/upload").
+		UploadFile("media", "a.jpg", strings.NewReader("hello")).
+		Post(context.Background())
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if got.MediaID != "m1" {
+		t.Fatalf("unexpected media id: %s", got.MediaID)
+	}
+}
+
+func TestRequestTokenRetryDisabled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeInvalidToken, "errmsg": "invalid credential"})
+	}))
+	defer server.Close()
+
+	provider := &rotatingTokenProvider{token: "old", next: "new"}
+	client, err := NewClient(ClientConfig{
+		BaseURL:       server.URL,
+		TokenProvider: provider,
+		TokenRetry:    TokenRetryPolicy{Disabled: true},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	_, err = NewTypedRequest[struct{}](client).Path("/cgi-bin/user/info").Get(context.Background())
+	if !IsTokenError(err) {
+		t.Fatalf("expected token error, got %v", err)
+	}
+	if provider.refreshes != 0 {
+		t.Fatalf("expected no refresh, got %d", provider.refreshes)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected 1 call, got %d", got)
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// errRespNil 表示 RESP 空回复（$-1 / *-1）。
+var errRespNil = errors.New("resp: nil reply")
+
+// RespError Redis 返回的错误回复（以 - 开头）。
+type RespError string
+
+func (e RespError) Error() string {
+	return "redis: " + string(e)
+}
+
+// respConn 最小化的 RESP2 连接，只支持请求-响应模式。
+type respConn struct {
+	conn      net.Conn
+	r         *bufio.Reader
+	w         *bufio.Writer
+	ioTimeout time.Duration
+	broken    bool
+}
+
+func newRespConn(conn net.Conn, ioTimeout time.Duration) *respConn {
+	return &respConn{
+		conn:      conn,
+		r:         bufio.NewReader(conn),
+		w:         bufio.NewWriter(conn),
+		ioTimeout: ioTimeout,
+	}
+}
+
+// do 发送命令并读取一个回复。
+// 网络或协议错误会将连接标记为不可复用；RespError 不影响连接状态。
+func (c *respConn) do(ctx context.Context, args ...string) (any, error) {
+	deadline := time.Now().Add(c.ioTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		c.broken = true
+		return nil, fmt.Errorf("set deadline: %w", err)
+	}
+	// ctx 取消时立即让阻塞中的读写返回；回调触发后截止时间已被改写，连接不再复用
+	stop := context.AfterFunc(ctx, func() { _ = c.conn.SetDeadline(time.Now()) })
+	defer func() {
+		if !stop() {
+			c.broken = true
+		}
+	}()
+
+	if err := c.writeCommand(args); err != nil {
+		c.broken = true
+		return nil, fmt.Errorf("write command: %w", c.ctxErr(ctx, err))
+	}
+
+	reply, err := c.readReply()
+	if err != nil {
+		var respErr RespError
+		if !errors.As(err, &respErr) && !errors.Is(err, errRespNil) {
+			c.broken = true
+			err = c.ctxErr(ctx, err)
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+// ctxErr 读写因 ctx 取消而中断时返回 ctx 的错误，便于调用方用 errors.Is 判断
+func (c *respConn) ctxErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return err
+}
+
+func (c *respConn) writeCommand(args []string) error {
+	c.w.WriteByte('*')
+	c.w.WriteString(strconv.Itoa(len(args)))
+	c.w.WriteString("\r\n")
+	for _, arg := range args {
+		c.w.WriteByte('$')
+		c.w.WriteString(strconv.Itoa(len(arg)))
+		c.w.WriteString("\r\n")
+		c.w.WriteString(arg)
+		c.w.WriteString("\r\n")
+	}
+	return c.w.Flush()
+}
+
+func (c *respConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("resp: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+// readReply 读取一个回复：简单字符串与批量字符串返回 string，整数返回 int64，数组返回 []any。
+func (c *respConn) readReply() (any, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, fmt.Errorf("resp: empty reply line")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RespError(line[1:])
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("resp: parse integer: %w", err)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("resp: parse bulk length: %w", err)
+		}
+		if n < 0 {
+			return nil, errRespNil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("resp: parse array length: %w", err)
+		}
+		if n < 0 {
+			return nil, errRespNil
+		}
+		// 元素为错误回复时继续读完整个数组，保证连接上不残留未读数据
+		items := make([]any, n)
+		var elemErr error
+		for i := range items {
+			item, err := c.readReply()
+			if err != nil && !errors.Is(err, errRespNil) {
+				var respErr RespError
+				if !errors.As(err, &respErr) {
+					return nil, err
+				}
+				if elemErr == nil {
+					elemErr = err
+				}
+				continue
+			}
+			items[i] = item
+		}
+		if elemErr != nil {
+			return nil, elemErr
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("resp: unknown reply type %q", line[0])
+	}
+}
+
+func (c *respConn) Close() error {
+	return c.conn.Close()
+}
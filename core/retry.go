@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 2 * time.Second
+	defaultRetryMultiplier     = 2.0
+)
+
+var (
+	// DefaultRetryableErrCodes 默认视为瞬时错误的微信 errcode：系统繁忙、接口限频。
+	DefaultRetryableErrCodes = []int{ErrCodeBusy, ErrCodeFreqLimit}
+	// DefaultRetryableStatusCodes 默认视为瞬时错误的 HTTP 状态码。
+	DefaultRetryableStatusCodes = []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+)
+
+// RetryPolicy 瞬时错误重试策略。
+// 零值表示不重试；GET 请求默认可重试，POST 请求需通过 RequestBuilder.Idempotent 显式声明。
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（含首次请求），<= 1 表示不重试
+	MaxAttempts int
+	// InitialBackoff 首次重试前的等待时长，<= 0 时使用默认值 200ms
+	InitialBackoff time.Duration
+	// MaxBackoff 单次等待时长上限，<= 0 时使用默认值 2s
+	MaxBackoff time.Duration
+	// Multiplier 退避倍数，< 1 时使用默认值 2
+	Multiplier float64
+	// Jitter 抖动比例，取值 [0, 1]，实际等待时长在 backoff*(1±Jitter) 内随机
+	Jitter float64
+	// RetryableErrCodes 需要重试的微信 errcode，nil 时使用 DefaultRetryableErrCodes
+	RetryableErrCodes []int
+	// RetryableStatusCodes 需要重试的 HTTP 状态码，nil 时使用 DefaultRetryableStatusCodes
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy 返回推荐的重试策略：最多 3 次尝试，200ms 起指数退避，20% 抖动。
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		Multiplier:     defaultRetryMultiplier,
+		Jitter:         0.2,
+	}
+}
+
+func (p RetryPolicy) normalize() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultRetryMaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaultRetryMultiplier
+	}
+	p.Jitter = min(max(p.Jitter, 0), 1)
+	if p.RetryableErrCodes == nil {
+		p.RetryableErrCodes = DefaultRetryableErrCodes
+	}
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = DefaultRetryableStatusCodes
+	}
+	p.RetryableErrCodes = slices.Clone(p.RetryableErrCodes)
+	p.RetryableStatusCodes = slices.Clone(p.RetryableStatusCodes)
+	return p
+}
+
+// transportError 发送请求或读取响应时的网络错误。
+// 只有这类错误按瞬时错误重试，中间件中止、构造请求失败等错误重试也不会成功。
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+
+func (e *transportError) Unwrap() error { return e.err }
+
+// retryable 判断一次请求结果是否属于可重试的瞬时错误，并返回原因描述。
+func (p RetryPolicy) retryable(resp RawResponse, wechatErr *WechatError, err error) (string, bool) {
+	if err != nil {
+		// 响应超过 MaxBodySize 时重试只会重新下载同样大小的响应体
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrResponseTooLarge) {
+			return "", false
+		}
+		if te := (*transportError)(nil); errors.As(err, &te) {
+			return "transport error", true
+		}
+		return "", false
+	}
+	if wechatErr != nil && slices.Contains(p.RetryableErrCodes, wechatErr.ErrCode) {
+		return "wechat errcode", true
+	}
+	if slices.Contains(p.RetryableStatusCodes, resp.StatusCode) {
+		return "http status", true
+	}
+	return "", false
+}
+
+// backoff 计算第 retry 次重试（从 1 开始）前的等待时长。
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := float64(p.InitialBackoff)
+	for range retry - 1 {
+		wait *= p.Multiplier
+		if wait >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	wait = min(wait, float64(p.MaxBackoff))
+	if p.Jitter > 0 {
+		wait *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(wait)
+}
+
+// sleepContext 等待 d，期间 ctx 结束则返回 ctx.Err()。
+// 若 ctx 的截止时间早于等待结束时间，直接返回 context.DeadlineExceeded 而不等待。
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
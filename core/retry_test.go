@@ -0,0 +1,194 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(t *testing.T, server *httptest.Server, policy RetryPolicy) *Client {
+	t.Helper()
+	client, err := NewClient(ClientConfig{BaseURL: server.URL, Retry: policy})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
+
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeBusy, "errmsg": "system busy"})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "name": "ok"})
+		}
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, fastRetryPolicy())
+	type resp struct {
+		Name string `json:"name"`
+	}
+	got, err := NewTypedRequest[resp](client).Path("/a").Get(context.Background())
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != "ok" {
+		t.Fatalf("unexpected name: %s", got.Name)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("expected 3 calls, got %d", n)
+	}
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeFreqLimit, "errmsg": "freq limit"})
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, fastRetryPolicy())
+	_, err := NewTypedRequest[struct{}](client).Path("/a").Get(context.Background())
+	var we *WechatError
+	if !errors.As(err, &we) || we.ErrCode != ErrCodeFreqLimit {
+		t.Fatalf("expected freq limit error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("expected 3 calls, got %d", n)
+	}
+}
+
+func TestRetryPostRequiresIdempotent(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0})
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, fastRetryPolicy())
+	if _, err := NewTypedRequest[struct{}](client).Path("/a").Body(map[string]any{}).Post(context.Background()); err == nil {
+		t.Fatal("non-idempotent post should not be retried")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected 1 call, got %d", n)
+	}
+
+	if _, err := NewTypedRequest[struct{}](client).Path("/a").Body(map[string]any{}).Idempotent(true).Post(context.Background()); err != nil {
+		t.Fatalf("idempotent post: %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected 2 calls, got %d", n)
+	}
+}
+
+func TestRetrySkipsMiddlewareAbort(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	errDenied := errors.New("denied")
+	var attempts int32
+	deny := MiddlewareFuncs{
+		Request: func(ctx context.Context, req *MiddlewareRequest) (context.Context, error) {
+			atomic.AddInt32(&attempts, 1)
+			return ctx, errDenied
+		},
+	}
+	client, err := NewClient(ClientConfig{BaseURL: server.URL, Retry: fastRetryPolicy(), Middlewares: []Middleware{deny}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	if _, err := client.Request().Path("/deny").Get(context.Background()); !errors.Is(err, errDenied) {
+		t.Fatalf("expected denied error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 1 {
+		t.Fatalf("middleware abort should not be retried, got %d attempts", n)
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Fatalf("request should not be sent, got %d calls", n)
+	}
+}
+
+func TestRetryTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var attempts int32
+	counter := MiddlewareFuncs{
+		Request: func(ctx context.Context, req *MiddlewareRequest) (context.Context, error) {
+			atomic.AddInt32(&attempts, 1)
+			return ctx, nil
+		},
+	}
+	client, err := NewClient(ClientConfig{BaseURL: server.URL, Retry: fastRetryPolicy(), Middlewares: []Middleware{counter}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	if _, err := client.Request().Path("/a").Get(context.Background()); err == nil {
+		t.Fatal("expected transport error")
+	}
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Fatalf("transport error should be retried, got %d attempts", n)
+	}
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if _, err := NewTypedRequest[struct{}](client).Path("/a").Get(ctx); err == nil {
+		t.Fatal("expected http status error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("backoff beyond deadline should not retry, got %d calls", n)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}.normalize()
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		if got := p.backoff(i + 1); got != w {
+			t.Fatalf("retry %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+
+	p.Jitter = 0.5
+	for range 100 {
+		got := p.backoff(1)
+		if got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("jittered backoff out of range: %v", got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// streamPeekSize 流式响应预读的字节数，用于区分 JSON 错误与二进制内容
+const streamPeekSize = 512
+
+// StreamResponse 流式响应，调用方读取完毕后必须关闭 Body。
+// 请求的 ctx 在 Body 读取完成前不能取消。
+type StreamResponse struct {
+	StatusCode  int
+	Header      http.Header
+	ContentType string
+	// Filename 取自 Content-Disposition，没有时为空
+	Filename string
+	// ContentLength 响应体长度，未知时为 -1
+	ContentLength int64
+	Body          io.ReadCloser
+}
+
+// GetStream 以流式模式发送 GET 请求，适用于下载媒体文件等大响应。
+// 微信返回 JSON 错误时转换为 *WechatError；返回 JSON 成功内容（如视频素材的下载地址）时同样以 Body 返回。
+func (b *RequestBuilder) GetStream(ctx context.Context) (*StreamResponse, error) {
+	b.stream = true
+	resp, err := b.execute(ctx, http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamResponse(resp)
+}
+
+// PostStream 以流式模式发送 POST 请求，行为同 GetStream
+func (b *RequestBuilder) PostStream(ctx context.Context) (*StreamResponse, error) {
+	b.stream = true
+	resp, err := b.Post(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamResponse(resp)
+}
+
+func newStreamResponse(resp RawResponse) (*StreamResponse, error) {
+	out := &StreamResponse{
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		ContentType:   resp.Header.Get("Content-Type"),
+		Filename:      dispositionFilename(resp.Header.Get("Content-Disposition")),
+		ContentLength: -1,
+		Body:          resp.stream,
+	}
+	if out.Body != nil {
+		if n, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+			out.ContentLength = n
+		}
+		return out, nil
+	}
+
+	// JSON 或错误状态码的响应体已完整读取
+	if IsJSONResponse(resp.Header, resp.Body) {
+		if wechatErr := parseWechatError(resp.Body); wechatErr != nil {
+			return nil, wechatErr
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, truncateBody(resp.Body, 256))
+	}
+	out.ContentLength = int64(len(resp.Body))
+	out.Body = io.NopCloser(bytes.NewReader(resp.Body))
+	return out, nil
+}
+
+// openStream 预读响应开头：JSON 或非 2xx 响应完整读取以便走 token 刷新与重试逻辑，其余保持流式。
+func (b *RequestBuilder) openStream(ctx context.Context, resp *http.Response) (RawResponse, error) {
+	raw := RawResponse{StatusCode: resp.StatusCode, Header: resp.Header}
+	if b.maxBody > 0 && resp.ContentLength > b.maxBody {
+		resp.Body.Close()
+		return RawResponse{}, fmt.Errorf("%w: content length %d exceeds %d", ErrResponseTooLarge, resp.ContentLength, b.maxBody)
+	}
+
+	br := bufio.NewReaderSize(resp.Body, streamPeekSize)
+	head, _ := br.Peek(streamPeekSize)
+	if IsJSONResponse(resp.Header, head) || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, err := readBody(br, b.maxBody)
+		if err != nil {
+			return RawResponse{}, &transportError{err}
+		}
+		raw.Body = body
+		b.client.logResponse(ctx, raw)
+		return raw, nil
+	}
+
+	var body io.Reader = br
+	if b.maxBody > 0 {
+		body = &maxBytesReader{r: br, remaining: b.maxBody}
+	}
+	raw.stream = struct {
+		io.Reader
+		io.Closer
+	}{body, resp.Body}
+	b.client.logResponse(ctx, raw)
+	return raw, nil
+}
+
+func (r RawResponse) closeStream() {
+	if r.stream != nil {
+		_ = r.stream.Close()
+	}
+}
+
+// readBody 读取完整响应体，maxBytes > 0 时超出限制返回 ErrResponseTooLarge
+func readBody(r io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+		return body, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, maxBytes)
+	}
+	return body, nil
+}
+
+// maxBytesReader 读取超过 remaining 字节时返回 ErrResponseTooLarge
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	if int64(n) > m.remaining {
+		n = int(m.remaining)
+		m.remaining = 0
+		m.err = ErrResponseTooLarge
+		return n, m.err
+	}
+	m.remaining -= int64(n)
+	return n, err
+}
+
+func dispositionFilename(disposition string) string {
+	if disposition == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(disposition)
+	if err != nil {
+		return ""
+	}
+	return params["filename"]
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetStream(t *testing.T) {
+	payload := bytes.Repeat([]byte("a"), 4096)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("media_id") {
+		case "file":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("Content-Disposition", `attachment; filename="MEDIA_ID.jpg"`)
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			_, _ = w.Write(payload)
+		case "video":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(`{"video_url":"https://example.com/v.mp4"}`))
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(`{"errcode":40007,"errmsg":"invalid media_id"}`))
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, &staticTokenProvider{token: "t"})
+	ctx := context.Background()
+
+	resp, err := client.Request().Path("/cgi-bin/media/get").Query("media_id", "file").GetStream(ctx)
+	if err != nil {
+		t.Fatalf("get stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.ContentType != "image/jpeg" || resp.Filename != "MEDIA_ID.jpg" || resp.ContentLength != int64(len(payload)) {
+		t.Fatalf("unexpected stream metadata: %+v", resp)
+	}
+	got, err := io.ReadAll(resp.Body)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("unexpected stream body: %d bytes, %v", len(got), err)
+	}
+
+	resp, err = client.Request().Path("/cgi-bin/media/get").Query("media_id", "video").GetStream(ctx)
+	if err != nil {
+		t.Fatalf("get json stream: %v", err)
+	}
+	var video struct {
+		VideoURL string `json:"video_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&video); err != nil || video.VideoURL == "" {
+		t.Fatalf("unexpected json body: %+v %v", video, err)
+	}
+
+	_, err = client.Request().Path("/cgi-bin/media/get").Query("media_id", "missing").GetStream(ctx)
+	var we *WechatError
+	if !errors.As(err, &we) || we.ErrCode != 40007 {
+		t.Fatalf("expected WechatError 40007, got %v", err)
+	}
+}
+
+func TestGetStreamMaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if r.URL.Query().Get("chunked") == "1" {
+			// 不设置 Content-Length，分块写出
+			for range 4 {
+				_, _ = w.Write(bytes.Repeat([]byte("b"), 1024))
+				w.(http.Flusher).Flush()
+			}
+			return
+		}
+		w.Header().Set("Content-Length", "4096")
+		_, _ = w.Write(bytes.Repeat([]byte("b"), 4096))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, nil)
+	ctx := context.Background()
+
+	_, err := client.Request().Path("/file").MaxBodySize(1024).GetStream(ctx)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge from content length, got %v", err)
+	}
+
+	resp, err := client.Request().Path("/file").Query("chunked", "1").MaxBodySize(2048).GetStream(ctx)
+	if err != nil {
+		t.Fatalf("get stream: %v", err)
+	}
+	defer resp.Body.Close()
+	got, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrResponseTooLarge) || len(got) != 2048 {
+		t.Fatalf("expected ErrResponseTooLarge after 2048 bytes, got %d bytes, %v", len(got), err)
+	}
+
+	_, err = client.Request().Path("/file").MaxBodySize(1024).Get(ctx)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge in buffered mode, got %v", err)
+	}
+}
+
+func TestMaxBodySizeNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(bytes.Repeat([]byte("b"), 100))
+	}))
+	defer server.Close()
+	client, err := NewClient(ClientConfig{BaseURL: server.URL, Retry: DefaultRetryPolicy()})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	if _, err := client.Request().Path("/file").MaxBodySize(10).Get(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("oversized response should not be retried, got %d calls", n)
+	}
+}
+
+func TestGetStreamRefreshesToken(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("access_token") != "new" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeInvalidToken, "errmsg": "invalid credential"})
+			return
+		}
+		w.Header().Set("Content-Type", "audio/amr")
+		_, _ = w.Write([]byte("#!AMR voice"))
+	}))
+	defer server.Close()
+
+	provider := &rotatingTokenProvider{token: "old", next: "new"}
+	client := newTestClient(t, server, provider)
+
+	resp, err := NewTypedRequest[struct{}](client).Path("/cgi-bin/media/get").GetStream(context.Background())
+	if err != nil {
+		t.Fatalf("get stream: %v", err)
+	}
+	defer resp.Body.Close()
+	got, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(string(got), "#!AMR") || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("unexpected result: %q after %d calls", got, calls)
+	}
+}
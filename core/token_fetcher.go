@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	AccessTokenPath       = "/cgi-bin/token"
+	StableAccessTokenPath = "/cgi-bin/stable_token"
+
+	// stableTokenForceInterval 微信限制 force_refresh 两次调用间隔至少 30 秒
+	stableTokenForceInterval = 30 * time.Second
+)
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type stableTokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	AppID        string `json:"appid"`
+	Secret       string `json:"secret"`
+	ForceRefresh bool   `json:"force_refresh"`
+}
+
+// NewAccessTokenFetcher 创建基于 /cgi-bin/token 的 TokenFetcher。
+// 注意：每次调用都会使上一次获取的 access_token 在 5 分钟后失效。
+func NewAccessTokenFetcher(client *Client, appID, appSecret string) TokenFetcher {
+	return func(ctx context.Context) (TokenFetchResult, error) {
+		resp, err := NewTypedRequest[accessTokenResponse](client).
+			Path(AccessTokenPath).
+			Query("grant_type", "client_credential").
+			Query("appid", appID).
+			Query("secret", appSecret).
+			WithoutToken().
+			Get(ctx)
+		if err != nil {
+			return TokenFetchResult{}, fmt.Errorf("request access token: %w", err)
+		}
+		return TokenFetchResult{Token: resp.AccessToken, ExpiresIn: resp.ExpiresIn}, nil
+	}
+}
+
+// StableTokenFetcher 基于 /cgi-bin/stable_token 的 token 获取器。
+// 普通模式下有效期内重复获取返回同一个 token，不会使其他实例持有的 token 失效；
+// 强制刷新模式受微信限频约束（30 秒一次、每日 20 次），超限时自动降级为普通模式。
+type StableTokenFetcher struct {
+	client    *Client
+	appID     string
+	appSecret string
+
+	mu        sync.Mutex
+	lastForce time.Time
+}
+
+// NewStableTokenFetcher 创建稳定版 access_token 获取器。
+// 通常将 Fetch 作为 TokenManagerConfig.Fetcher，ForceFetch 作为 TokenManagerConfig.RefreshFetcher。
+func NewStableTokenFetcher(client *Client, appID, appSecret string) *StableTokenFetcher {
+	return &StableTokenFetcher{client: client, appID: appID, appSecret: appSecret}
+}
+
+// Fetch 以普通模式（force_refresh=false）获取 access_token。
+func (f *StableTokenFetcher) Fetch(ctx context.Context) (TokenFetchResult, error) {
+	return f.fetch(ctx, false)
+}
+
+// ForceFetch 以强制刷新模式（force_refresh=true）获取 access_token。
+// 距上次强制刷新不足 30 秒，或微信返回限频错误时，降级为普通模式获取。
+func (f *StableTokenFetcher) ForceFetch(ctx context.Context) (TokenFetchResult, error) {
+	f.mu.Lock()
+	if !f.lastForce.IsZero() && time.Since(f.lastForce) < stableTokenForceInterval {
+		f.mu.Unlock()
+		f.client.logger.DebugContext(ctx, "stable token force refresh throttled, using normal mode")
+		return f.fetch(ctx, false)
+	}
+	f.lastForce = time.Now()
+	f.mu.Unlock()
+
+	result, err := f.fetch(ctx, true)
+	if err == nil {
+		return result, nil
+	}
+
+	var we *WechatError
+	if errors.As(err, &we) && (we.ErrCode == ErrCodeFreqLimit || we.ErrCode == ErrCodeDailyQuotaLimit) {
+		f.client.logger.WarnContext(ctx, "stable token force refresh rate limited, using normal mode", slog.Any("error", err))
+		return f.fetch(ctx, false)
+	}
+	return TokenFetchResult{}, err
+}
+
+func (f *StableTokenFetcher) fetch(ctx context.Context, force bool) (TokenFetchResult, error) {
+	resp, err := NewTypedRequest[accessTokenResponse](f.client).
+		Path(StableAccessTokenPath).
+		Body(stableTokenRequest{
+			GrantType:    "client_credential",
+			AppID:        f.appID,
+			Secret:       f.appSecret,
+			ForceRefresh: force,
+		}).
+		WithoutToken().
+		Idempotent(!force).
+		Post(ctx)
+	if err != nil {
+		return TokenFetchResult{}, fmt.Errorf("request stable access token: %w", err)
+	}
+	return TokenFetchResult{Token: resp.AccessToken, ExpiresIn: resp.ExpiresIn}, nil
+}
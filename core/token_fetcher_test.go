@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStableTokenFetcher(t *testing.T) {
+	var forced []bool
+	limited := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != StableAccessTokenPath || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req stableTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if req.GrantType != "client_credential" || req.AppID != "appid" || req.Secret != "secret" {
+			t.Errorf("unexpected body: %+v", req)
+		}
+		forced = append(forced, req.ForceRefresh)
+		if req.ForceRefresh && limited {
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": ErrCodeDailyQuotaLimit, "errmsg": "reach max api daily quota limit"})
+			return
+		}
+		token := "stable"
+		if req.ForceRefresh {
+			token = "forced"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": token, "expires_in": 7200})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	fetcher := NewStableTokenFetcher(client, "appid", "secret")
+	ctx := context.Background()
+
+	got, err := fetcher.Fetch(ctx)
+	if err != nil || got.Token != "stable" || got.ExpiresIn != 7200 {
+		t.Fatalf("fetch: %+v, %v", got, err)
+	}
+
+	got, err = fetcher.ForceFetch(ctx)
+	if err != nil || got.Token != "forced" {
+		t.Fatalf("force fetch: %+v, %v", got, err)
+	}
+
+	// 30 秒内再次强制刷新降级为普通模式
+	got, err = fetcher.ForceFetch(ctx)
+	if err != nil || got.Token != "stable" {
+		t.Fatalf("throttled force fetch: %+v, %v", got, err)
+	}
+
+	// 限频错误降级为普通模式
+	limited = true
+	fetcher.lastForce = time.Now().Add(-time.Minute)
+	got, err = fetcher.ForceFetch(ctx)
+	if err != nil || got.Token != "stable" {
+		t.Fatalf("rate limited force fetch: %+v, %v", got, err)
+	}
+
+	want := []bool{false, true, false, true, false}
+	if len(forced) != len(want) {
+		t.Fatalf("unexpected calls: %v", forced)
+	}
+	for i := range want {
+		if forced[i] != want[i] {
+			t.Fatalf("unexpected force flags: %v", forced)
+		}
+	}
+}
+
+func TestTokenManagerUsesRefreshFetcher(t *testing.T) {
+	m, err := NewTokenManager(TokenManagerConfig{
+		Cache:    newTokenTestCache(),
+		CacheKey: "token-key",
+		Fetcher: func(ctx context.Context) (TokenFetchResult, error) {
+			return TokenFetchResult{Token: "normal", ExpiresIn: 7200}, nil
+		},
+		RefreshFetcher: func(ctx context.Context) (TokenFetchResult, error) {
+			return TokenFetchResult{Token: "forced", ExpiresIn: 7200}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	if token, _ := m.GetToken(context.Background()); token != "normal" {
+		t.Fatalf("unexpected token: %s", token)
+	}
+	if token, _ := m.RefreshToken(context.Background()); token != "forced" {
+		t.Fatalf("unexpected refreshed token: %s", token)
+	}
+}
@@ -1,14 +1,22 @@
 package core
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"sync"
 	"time"
 )
 
-const defaultExpireBufferSeconds = 300
+const (
+	defaultExpireBufferSeconds = 300
+	defaultLockTTL             = 10 * time.Second
+	defaultLockPollInterval    = 100 * time.Millisecond
+	lockKeySuffix              = ":lock"
+	expiresAtKeySuffix         = ":expires_at"
+)
 
 type TokenFetchResult struct {
 	Token     string
@@ -21,8 +29,15 @@ type TokenManagerConfig struct {
 	Cache               Cache
 	CacheKey            string
 	Fetcher             TokenFetcher
+	RefreshFetcher      TokenFetcher
 	Logger              *slog.Logger
 	ExpireBufferSeconds int
+	Locker              Locker
+	LockTTL             time.Duration
+	// LockWaitTimeout 未持锁实例等待共享缓存出现新 token 的最长时间，不得小于 LockTTL，
+	// 否则持锁实例崩溃时其余实例会在锁过期前放弃；为 0 时使用 LockTTL 加一个轮询间隔
+	LockWaitTimeout  time.Duration
+	LockPollInterval time.Duration
 }
 
 type tokenCall struct {
@@ -35,8 +50,13 @@ type TokenManager struct {
 	cache               Cache
 	cacheKey            string
 	fetcher             TokenFetcher
+	refreshFetcher      TokenFetcher
 	logger              *slog.Logger
 	expireBufferSeconds int
+	locker              Locker
+	lockTTL             time.Duration
+	lockWaitTimeout     time.Duration
+	lockPollInterval    time.Duration
 
 	mu       sync.Mutex
 	inflight *tokenCall
@@ -58,17 +78,34 @@ func NewTokenManager(cfg TokenManagerConfig) (*TokenManager, error) {
 		logger = slog.Default()
 	}
 
+	refreshFetcher := cfg.RefreshFetcher
+	if refreshFetcher == nil {
+		refreshFetcher = cfg.Fetcher
+	}
+
 	expireBufferSeconds := cfg.ExpireBufferSeconds
 	if expireBufferSeconds <= 0 {
 		expireBufferSeconds = defaultExpireBufferSeconds
 	}
 
+	lockTTL := cmp.Or(cfg.LockTTL, defaultLockTTL)
+	lockPollInterval := cmp.Or(cfg.LockPollInterval, defaultLockPollInterval)
+	lockWaitTimeout := cmp.Or(cfg.LockWaitTimeout, lockTTL+lockPollInterval)
+	if cfg.Locker != nil && lockWaitTimeout < lockTTL {
+		return nil, fmt.Errorf("lock wait timeout %s must not be shorter than lock ttl %s", lockWaitTimeout, lockTTL)
+	}
+
 	return &TokenManager{
 		cache:               cfg.Cache,
 		cacheKey:            cfg.CacheKey,
 		fetcher:             cfg.Fetcher,
+		refreshFetcher:      refreshFetcher,
 		logger:              logger,
 		expireBufferSeconds: expireBufferSeconds,
+		locker:              cfg.Locker,
+		lockTTL:             lockTTL,
+		lockWaitTimeout:     lockWaitTimeout,
+		lockPollInterval:    lockPollInterval,
 	}, nil
 }
 
@@ -83,9 +120,86 @@ func (m *TokenManager) RefreshToken(ctx context.Context) (string, error) {
 	return m.do(ctx, true)
 }
 
+// Renew 供后台刷新使用：共享缓存中的 token 缺失或距过期不足 ahead 时，通过 Fetcher 重新获取。
+// 配置 Locker 时只有持锁实例会向微信获取，其余实例稍后重新检查。
+// 返回距下一次需要续期的等待时长。
+func (m *TokenManager) Renew(ctx context.Context, ahead time.Duration) (time.Duration, error) {
+	if next, ok := m.renewDelay(ctx, ahead); ok {
+		return next, nil
+	}
+
+	if m.locker != nil {
+		lockKey := m.cacheKey + lockKeySuffix
+		locked, err := m.locker.TryLock(ctx, lockKey, m.lockTTL)
+		if err != nil {
+			return 0, fmt.Errorf("acquire token lock: %w", err)
+		}
+		if !locked {
+			return m.lockPollInterval, nil
+		}
+		defer m.unlock(ctx, lockKey)
+
+		if next, ok := m.renewDelay(ctx, ahead); ok {
+			return next, nil
+		}
+	}
+
+	_, err := m.singleflight(ctx, false, func() (string, error) {
+		// 双检：等待期间本进程的其他请求可能已获取新 token
+		if _, ok := m.renewDelay(ctx, ahead); ok {
+			return "", nil
+		}
+		return m.fetch(ctx, false)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if next, ok := m.renewDelay(ctx, ahead); ok {
+		return next, nil
+	}
+	return ahead, nil
+}
+
+// renewDelay 根据共享缓存中记录的过期时间计算距续期的等待时长，token 缺失或即将过期时返回 false。
+func (m *TokenManager) renewDelay(ctx context.Context, ahead time.Duration) (time.Duration, bool) {
+	if _, ok := m.cache.Get(ctx, m.cacheKey); !ok {
+		return 0, false
+	}
+	remaining, ok := m.remainingTTL(ctx)
+	if !ok {
+		// 有效期未知时不主动刷新（否则会挤掉其他实例正在使用的 token），稍后重新检查，token 过期后再获取
+		return cmp.Or(ahead, defaultRefreshAhead), true
+	}
+	if remaining <= ahead {
+		return 0, false
+	}
+	return remaining - ahead, true
+}
+
+// remainingTTL 返回缓存中 token 的剩余有效期。
+// 优先读取过期时间记录；升级前写入的 token 没有该记录，退回缓存项自身的 TTL（需要 Cache 实现 TTLCache）。
+func (m *TokenManager) remainingTTL(ctx context.Context) (time.Duration, bool) {
+	if raw, ok := m.cache.Get(ctx, m.cacheKey+expiresAtKeySuffix); ok {
+		if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return time.Until(time.UnixMilli(ms)), true
+		}
+	}
+	if ttlCache, ok := m.cache.(TTLCache); ok {
+		if ttl, ok := ttlCache.TTL(ctx, m.cacheKey); ok && ttl > 0 {
+			return ttl, true
+		}
+	}
+	return 0, false
+}
+
 func (m *TokenManager) do(ctx context.Context, force bool) (string, error) {
+	return m.singleflight(ctx, !force, func() (string, error) { return m.fetchAndStore(ctx, force) })
+}
+
+// singleflight 进程内合并并发获取，useCache 为 true 时先读缓存。
+func (m *TokenManager) singleflight(ctx context.Context, useCache bool, fn func() (string, error)) (string, error) {
 	m.mu.Lock()
-	if !force {
+	if useCache {
 		if token, ok := m.cache.Get(ctx, m.cacheKey); ok {
 			m.mu.Unlock()
 			return token, nil
@@ -102,7 +216,7 @@ func (m *TokenManager) do(ctx context.Context, force bool) (string, error) {
 	m.inflight = call
 	m.mu.Unlock()
 
-	token, err := m.fetchAndStore(ctx, force)
+	token, err := fn()
 	call.token = token
 	call.err = err
 	close(call.done)
@@ -122,8 +236,67 @@ func (m *TokenManager) fetchAndStore(ctx context.Context, force bool) (string, e
 			return token, nil
 		}
 	}
+	if m.locker == nil {
+		return m.fetch(ctx, force)
+	}
+	return m.fetchWithLock(ctx, force)
+}
+
+// fetchWithLock 跨进程单飞：持锁实例负责向微信获取 token，其余实例轮询共享缓存。
+// 强制刷新时会等待缓存中的 token 变为与刷新前不同的值。
+func (m *TokenManager) fetchWithLock(ctx context.Context, force bool) (string, error) {
+	var stale string
+	if force {
+		stale, _ = m.cache.Get(ctx, m.cacheKey)
+	}
 
-	result, err := m.fetcher(ctx)
+	lockKey := m.cacheKey + lockKeySuffix
+	deadline := time.Now().Add(m.lockWaitTimeout)
+	for {
+		locked, err := m.locker.TryLock(ctx, lockKey, m.lockTTL)
+		if err != nil {
+			m.logger.WarnContext(ctx, "acquire token lock failed, fetching without lock", slog.String("key", lockKey), slog.Any("error", err))
+			return m.fetch(ctx, force)
+		}
+		if locked {
+			return m.fetchLocked(ctx, lockKey, force, stale)
+		}
+
+		if err := sleepContext(ctx, m.lockPollInterval); err != nil {
+			return "", err
+		}
+		if token, ok := m.cache.Get(ctx, m.cacheKey); ok && token != stale {
+			return token, nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("wait for token refresh by another instance: timeout after %s", m.lockWaitTimeout)
+		}
+	}
+}
+
+func (m *TokenManager) fetchLocked(ctx context.Context, lockKey string, force bool, stale string) (string, error) {
+	defer m.unlock(ctx, lockKey)
+
+	// 双检：等锁期间其他实例可能已写入新 token
+	if token, ok := m.cache.Get(ctx, m.cacheKey); ok && (!force || token != stale) {
+		return token, nil
+	}
+	return m.fetch(ctx, force)
+}
+
+func (m *TokenManager) unlock(ctx context.Context, lockKey string) {
+	if err := m.locker.Unlock(context.WithoutCancel(ctx), lockKey); err != nil {
+		m.logger.WarnContext(ctx, "release token lock failed", slog.String("key", lockKey), slog.Any("error", err))
+	}
+}
+
+func (m *TokenManager) fetch(ctx context.Context, force bool) (string, error) {
+	fetcher := m.fetcher
+	if force {
+		fetcher = m.refreshFetcher
+	}
+
+	result, err := fetcher(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -136,6 +309,11 @@ func (m *TokenManager) fetchAndStore(ctx context.Context, force bool) (string, e
 	if err := m.cache.Set(ctx, m.cacheKey, result.Token, ttl); err != nil {
 		m.logger.WarnContext(ctx, "cache token failed", slog.String("key", m.cacheKey), slog.Any("error", err))
 	}
+	// 单独记录缓存过期时间，供各实例的后台刷新判断何时续期；token 本身仍以原始字符串缓存
+	expiresAt := strconv.FormatInt(time.Now().Add(ttl).UnixMilli(), 10)
+	if err := m.cache.Set(ctx, m.cacheKey+expiresAtKeySuffix, expiresAt, ttl); err != nil {
+		m.logger.WarnContext(ctx, "cache token expiry failed", slog.String("key", m.cacheKey), slog.Any("error", err))
+	}
 
 	return result.Token, nil
 }
@@ -38,16 +38,33 @@ func (r *TypedRequest[T]) WithoutToken() *TypedRequest[T] {
 	return r
 }
 
+func (r *TypedRequest[T]) Idempotent(idempotent bool) *TypedRequest[T] {
+	r.builder.Idempotent(idempotent)
+	return r
+}
+
 func (r *TypedRequest[T]) UploadFile(field, fileName string, reader io.Reader) *TypedRequest[T] {
 	r.builder.UploadFile(field, fileName, reader)
 	return r
 }
 
+// UploadPart 添加一个上传文件，见 RequestBuilder.UploadPart
+func (r *TypedRequest[T]) UploadPart(part FilePart) *TypedRequest[T] {
+	r.builder.UploadPart(part)
+	return r
+}
+
 func (r *TypedRequest[T]) UploadField(key, value string) *TypedRequest[T] {
 	r.builder.UploadField(key, value)
 	return r
 }
 
+// MaxBodySize 限制响应体大小，超出时返回 ErrResponseTooLarge；<= 0 表示不限制。
+func (r *TypedRequest[T]) MaxBodySize(n int64) *TypedRequest[T] {
+	r.builder.MaxBodySize(n)
+	return r
+}
+
 func (r *TypedRequest[T]) Get(ctx context.Context) (T, error) {
 	resp, err := r.builder.Get(ctx)
 	if err != nil {
@@ -65,3 +82,13 @@ func (r *TypedRequest[T]) Post(ctx context.Context) (T, error) {
 	}
 	return DecodeWechat[T](resp.StatusCode, resp.Body)
 }
+
+// GetStream 以流式模式发送 GET 请求，见 RequestBuilder.GetStream
+func (r *TypedRequest[T]) GetStream(ctx context.Context) (*StreamResponse, error) {
+	return r.builder.GetStream(ctx)
+}
+
+// PostStream 以流式模式发送 POST 请求，见 RequestBuilder.PostStream
+func (r *TypedRequest[T]) PostStream(ctx context.Context) (*StreamResponse, error) {
+	return r.builder.PostStream(ctx)
+}
@@ -0,0 +1,266 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// 消息加解密错误码，与微信官方 WXBizMsgCrypt 保持一致
+const (
+	MsgCryptValidateSignatureError = -40001
+	MsgCryptParseError             = -40002
+	MsgCryptComputeSignatureError  = -40003
+	MsgCryptIllegalAESKey          = -40004
+	MsgCryptValidateAppIDError     = -40005
+	MsgCryptEncryptAESError        = -40006
+	MsgCryptDecryptAESError        = -40007
+	MsgCryptIllegalBuffer          = -40008
+	MsgCryptEncodeBase64Error      = -40009
+	MsgCryptDecodeBase64Error      = -40010
+	MsgCryptGenReturnError         = -40011
+)
+
+const (
+	encodingAESKeyLength = 43
+	// msgCryptBlockSize 微信消息加密使用 32 字节块做 PKCS7 填充
+	msgCryptBlockSize = 32
+	msgRandomLength   = 16
+)
+
+// MsgCryptError 消息加解密错误，Code 为官方错误码
+type MsgCryptError struct {
+	Code int
+	Err  error
+}
+
+func (e *MsgCryptError) Error() string {
+	return fmt.Sprintf("msg crypt error: [%d] %v", e.Code, e.Err)
+}
+
+func (e *MsgCryptError) Unwrap() error {
+	return e.Err
+}
+
+func newMsgCryptError(code int, format string, args ...any) *MsgCryptError {
+	return &MsgCryptError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// MsgCrypto 微信消息加解密（安全模式）
+// 使用 AES-256-CBC，明文格式为 random(16B) + msg_len(4B, 网络字节序) + msg + appid，
+// 适用于公众号、小程序等产品的消息推送。
+type MsgCrypto struct {
+	token string
+	key   []byte
+	appID string
+}
+
+// NewMsgCrypto 创建消息加解密实例
+// token: 后台配置的 Token
+// encodingAESKey: 后台配置的 43 位 EncodingAESKey
+// appID: 公众号/小程序 AppID，解密时校验尾部 AppID，为空时不校验
+func NewMsgCrypto(token, encodingAESKey, appID string) (*MsgCrypto, error) {
+	if len(encodingAESKey) != encodingAESKeyLength {
+		return nil, newMsgCryptError(MsgCryptIllegalAESKey, "encoding aes key must be %d characters", encodingAESKeyLength)
+	}
+	key, err := base64.StdEncoding.DecodeString(encodingAESKey + "=")
+	if err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptIllegalAESKey, Err: err}
+	}
+	return &MsgCrypto{token: token, key: key, appID: appID}, nil
+}
+
+// Signature 计算消息签名 msg_signature
+func (c *MsgCrypto) Signature(timestamp, nonce, encrypted string) string {
+	return SHA1Sign(c.token, timestamp, nonce, encrypted)
+}
+
+// VerifySignature 校验消息签名 msg_signature
+func (c *MsgCrypto) VerifySignature(msgSignature, timestamp, nonce, encrypted string) bool {
+	return VerifyMsgSignature(msgSignature, timestamp, nonce, c.token, encrypted)
+}
+
+// Encrypt 加密消息，返回 Base64 密文
+func (c *MsgCrypto) Encrypt(msg []byte) (string, error) {
+	random := make([]byte, msgRandomLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", &MsgCryptError{Code: MsgCryptEncryptAESError, Err: err}
+	}
+	return c.encrypt(random, msg)
+}
+
+func (c *MsgCrypto) encrypt(random, msg []byte) (string, error) {
+	plaintext := make([]byte, 0, msgRandomLength+4+len(msg)+len(c.appID)+msgCryptBlockSize)
+	plaintext = append(plaintext, random...)
+	plaintext = binary.BigEndian.AppendUint32(plaintext, uint32(len(msg)))
+	plaintext = append(plaintext, msg...)
+	plaintext = append(plaintext, c.appID...)
+	plaintext = PKCS7Pad(plaintext, msgCryptBlockSize)
+
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return "", &MsgCryptError{Code: MsgCryptEncryptAESError, Err: err}
+	}
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, c.key[:aes.BlockSize]).CryptBlocks(ciphertext, plaintext)
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt 解密 Base64 密文并校验尾部 AppID，返回消息明文
+func (c *MsgCrypto) Decrypt(encrypted string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptDecodeBase64Error, Err: err}
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, &MsgCryptError{Code: MsgCryptDecryptAESError, Err: ErrInvalidBlockSize}
+	}
+
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptDecryptAESError, Err: err}
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, c.key[:aes.BlockSize]).CryptBlocks(plaintext, ciphertext)
+
+	plaintext, err = PKCS7Unpad(plaintext, msgCryptBlockSize)
+	if err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptIllegalBuffer, Err: err}
+	}
+	if len(plaintext) < msgRandomLength+4 {
+		return nil, newMsgCryptError(MsgCryptIllegalBuffer, "plaintext too short")
+	}
+
+	content := plaintext[msgRandomLength:]
+	msgLen := int(binary.BigEndian.Uint32(content[:4]))
+	if msgLen > len(content)-4 {
+		return nil, newMsgCryptError(MsgCryptIllegalBuffer, "invalid message length %d", msgLen)
+	}
+	msg := content[4 : 4+msgLen]
+	appID := content[4+msgLen:]
+
+	if c.appID != "" && subtle.ConstantTimeCompare(appID, []byte(c.appID)) != 1 {
+		return nil, newMsgCryptError(MsgCryptValidateAppIDError, "appid mismatch")
+	}
+	return msg, nil
+}
+
+type encryptedXMLRequest struct {
+	XMLName    xml.Name `xml:"xml"`
+	ToUserName string   `xml:"ToUserName"`
+	Encrypt    string   `xml:"Encrypt"`
+}
+
+type encryptedJSONRequest struct {
+	ToUserName string `json:"ToUserName"`
+	Encrypt    string `json:"Encrypt"`
+}
+
+type xmlCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+type encryptedXMLResponse struct {
+	XMLName      xml.Name `xml:"xml"`
+	Encrypt      xmlCDATA `xml:"Encrypt"`
+	MsgSignature xmlCDATA `xml:"MsgSignature"`
+	TimeStamp    string   `xml:"TimeStamp"`
+	Nonce        xmlCDATA `xml:"Nonce"`
+}
+
+type encryptedJSONResponse struct {
+	Encrypt      string `json:"Encrypt"`
+	MsgSignature string `json:"MsgSignature"`
+	TimeStamp    int64  `json:"TimeStamp"`
+	Nonce        string `json:"Nonce"`
+}
+
+// DecryptXML 校验 msg_signature 并解密 XML 格式的加密消息体，返回明文 XML
+func (c *MsgCrypto) DecryptXML(msgSignature, timestamp, nonce string, body []byte) ([]byte, error) {
+	var req encryptedXMLRequest
+	if err := xml.Unmarshal(body, &req); err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptParseError, Err: err}
+	}
+	return c.decryptEnvelope(msgSignature, timestamp, nonce, req.Encrypt)
+}
+
+// DecryptJSON 校验 msg_signature 并解密 JSON 格式的加密消息体，返回明文 JSON
+func (c *MsgCrypto) DecryptJSON(msgSignature, timestamp, nonce string, body []byte) ([]byte, error) {
+	var req encryptedJSONRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptParseError, Err: err}
+	}
+	return c.decryptEnvelope(msgSignature, timestamp, nonce, req.Encrypt)
+}
+
+func (c *MsgCrypto) decryptEnvelope(msgSignature, timestamp, nonce, encrypted string) ([]byte, error) {
+	if encrypted == "" {
+		return nil, newMsgCryptError(MsgCryptParseError, "missing Encrypt field")
+	}
+	if !c.VerifySignature(msgSignature, timestamp, nonce, encrypted) {
+		return nil, newMsgCryptError(MsgCryptValidateSignatureError, "invalid msg_signature")
+	}
+	return c.Decrypt(encrypted)
+}
+
+// EncryptXML 加密明文回复并封装为 XML 格式（Encrypt/MsgSignature/TimeStamp/Nonce）
+func (c *MsgCrypto) EncryptXML(msg []byte, timestamp, nonce string) ([]byte, error) {
+	encrypted, err := c.Encrypt(msg)
+	if err != nil {
+		return nil, err
+	}
+	out, err := xml.Marshal(encryptedXMLResponse{
+		Encrypt:      xmlCDATA{encrypted},
+		MsgSignature: xmlCDATA{c.Signature(timestamp, nonce, encrypted)},
+		TimeStamp:    timestamp,
+		Nonce:        xmlCDATA{nonce},
+	})
+	if err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptGenReturnError, Err: err}
+	}
+	return out, nil
+}
+
+// EncryptJSON 加密明文回复并封装为 JSON 格式（Encrypt/MsgSignature/TimeStamp/Nonce）
+func (c *MsgCrypto) EncryptJSON(msg []byte, timestamp, nonce string) ([]byte, error) {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptGenReturnError, Err: fmt.Errorf("invalid timestamp: %w", err)}
+	}
+	encrypted, err := c.Encrypt(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(encryptedJSONResponse{
+		Encrypt:      encrypted,
+		MsgSignature: c.Signature(timestamp, nonce, encrypted),
+		TimeStamp:    ts,
+		Nonce:        nonce,
+	}); err != nil {
+		return nil, &MsgCryptError{Code: MsgCryptGenReturnError, Err: err}
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// MsgCryptErrorCode 提取错误中的官方错误码，非 MsgCryptError 时返回 0 与 false
+func MsgCryptErrorCode(err error) (int, bool) {
+	var e *MsgCryptError
+	if errors.As(err, &e) {
+		return e.Code, true
+	}
+	return 0, false
+}
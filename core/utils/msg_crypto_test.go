@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMsgToken  = "token"
+	testMsgAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	testMsgAppID  = "wx1234567890"
+)
+
+func newTestMsgCrypto(t *testing.T, appID string) *MsgCrypto {
+	t.Helper()
+	c, err := NewMsgCrypto(testMsgToken, testMsgAESKey, appID)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewMsgCryptoIllegalKey(t *testing.T) {
+	_, err := NewMsgCrypto(testMsgToken, "short", testMsgAppID)
+	code, ok := MsgCryptErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, MsgCryptIllegalAESKey, code)
+}
+
+func TestMsgCryptoEncryptDecrypt(t *testing.T) {
+	c := newTestMsgCrypto(t, testMsgAppID)
+	msg := []byte("<xml><Content><![CDATA[你好]]></Content></xml>")
+
+	encrypted, err := c.Encrypt(msg)
+	require.NoError(t, err)
+
+	raw, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err)
+	assert.Zero(t, len(raw)%msgCryptBlockSize)
+
+	got, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+}
+
+func TestMsgCryptoDecryptErrors(t *testing.T) {
+	c := newTestMsgCrypto(t, testMsgAppID)
+	other := newTestMsgCrypto(t, "wx-other")
+	foreign, err := other.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		encrypted string
+		wantCode  int
+	}{
+		{name: "invalid base64", encrypted: "!!!", wantCode: MsgCryptDecodeBase64Error},
+		{name: "invalid block size", encrypted: base64.StdEncoding.EncodeToString([]byte("short")), wantCode: MsgCryptDecryptAESError},
+		{name: "appid mismatch", encrypted: foreign, wantCode: MsgCryptValidateAppIDError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.Decrypt(tt.encrypted)
+			code, ok := MsgCryptErrorCode(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantCode, code)
+		})
+	}
+}
+
+func TestMsgCryptoSkipAppIDCheck(t *testing.T) {
+	sender := newTestMsgCrypto(t, testMsgAppID)
+	encrypted, err := sender.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+
+	got, err := newTestMsgCrypto(t, "").Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), got)
+}
+
+func TestMsgCryptoXMLEnvelope(t *testing.T) {
+	c := newTestMsgCrypto(t, testMsgAppID)
+	msg := []byte("<xml><MsgType><![CDATA[text]]></MsgType></xml>")
+
+	out, err := c.EncryptXML(msg, "1700000000", "nonce")
+	require.NoError(t, err)
+
+	var envelope struct {
+		Encrypt      string `xml:"Encrypt"`
+		MsgSignature string `xml:"MsgSignature"`
+		TimeStamp    string `xml:"TimeStamp"`
+		Nonce        string `xml:"Nonce"`
+	}
+	require.NoError(t, xml.Unmarshal(out, &envelope))
+	assert.Equal(t, "1700000000", envelope.TimeStamp)
+	assert.Equal(t, "nonce", envelope.Nonce)
+	assert.Equal(t, c.Signature("1700000000", "nonce", envelope.Encrypt), envelope.MsgSignature)
+
+	body := []byte("<xml><ToUserName><![CDATA[gh_1]]></ToUserName><Encrypt><![CDATA[" + envelope.Encrypt + "]]></Encrypt></xml>")
+	got, err := c.DecryptXML(envelope.MsgSignature, "1700000000", "nonce", body)
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+
+	_, err = c.DecryptXML("bad", "1700000000", "nonce", body)
+	code, _ := MsgCryptErrorCode(err)
+	assert.Equal(t, MsgCryptValidateSignatureError, code)
+
+	_, err = c.DecryptXML(envelope.MsgSignature, "1700000000", "nonce", []byte("<xml>"))
+	code, _ = MsgCryptErrorCode(err)
+	assert.Equal(t, MsgCryptParseError, code)
+}
+
+func TestMsgCryptoJSONEnvelope(t *testing.T) {
+	c := newTestMsgCrypto(t, testMsgAppID)
+	msg := []byte(`{"MsgType":"event","Event":"user_enter_tempsession"}`)
+
+	out, err := c.EncryptJSON(msg, "1700000000", "nonce")
+	require.NoError(t, err)
+
+	var envelope struct {
+		Encrypt      string `json:"Encrypt"`
+		MsgSignature string `json:"MsgSignature"`
+		TimeStamp    int64  `json:"TimeStamp"`
+		Nonce        string `json:"Nonce"`
+	}
+	require.NoError(t, json.Unmarshal(out, &envelope))
+	assert.Equal(t, int64(1700000000), envelope.TimeStamp)
+
+	body, err := json.Marshal(map[string]string{"ToUserName": "gh_1", "Encrypt": envelope.Encrypt})
+	require.NoError(t, err)
+	got, err := c.DecryptJSON(envelope.MsgSignature, "1700000000", "nonce", body)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(msg, got))
+
+	_, err = c.EncryptJSON(msg, "not-a-number", "nonce")
+	code, _ := MsgCryptErrorCode(err)
+	assert.Equal(t, MsgCryptGenReturnError, code)
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
 )
 
 func TestNewValidation(t *testing.T) {
@@ -135,3 +137,50 @@ func TestTypedRequest(t *testing.T) {
 		t.Fatalf("unexpected msgid: %d", resp.MsgID)
 	}
 }
+
+func TestStableToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case core.StableAccessTokenPath:
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "stable-1", "expires_in": 7200})
+		case "/wxa/business/getuserphonenumber":
+			if got := r.URL.Query().Get("access_token"); got != "stable-1" {
+				t.Fatalf("expected access_token=stable-1, got %s", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "phone_info": map[string]any{"phoneNumber": "1"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL, StableToken: true})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := client.GetPhoneNumber(context.Background(), GetPhoneNumberRequest{Code: "123"}); err != nil {
+		t.Fatalf("get phone number: %v", err)
+	}
+}
+
+// newTestAPIClient 创建指向测试服务的客户端，access_token 请求由测试服务自动应答，其余请求交给 handler
+func newTestAPIClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == accessTokenPath {
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token-1", "expires_in": 7200})
+			return
+		}
+		if got := r.URL.Query().Get("access_token"); got != "token-1" {
+			t.Errorf("unexpected access_token: %q", got)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
@@ -0,0 +1,216 @@
+package miniprogram
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+)
+
+// MsgType 消息类型
+type MsgType string
+
+const (
+	MsgTypeText            MsgType = "text"
+	MsgTypeImage           MsgType = "image"
+	MsgTypeMiniprogramPage MsgType = "miniprogrampage"
+	MsgTypeEvent           MsgType = "event"
+)
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventUserEnterTempSession       EventType = "user_enter_tempsession"
+	EventSubscribeMsgPopup          EventType = "subscribe_msg_popup_event"
+	EventSubscribeMsgChange         EventType = "subscribe_msg_change_event"
+	EventSubscribeMsgSent           EventType = "subscribe_msg_sent_event"
+	EventMediaCheck                 EventType = "wxa_media_check"
+	EventTradeManageRemindAccessAPI EventType = "trade_manage_remind_access_api"
+	EventTradeManageRemindShipping  EventType = "trade_manage_remind_shipping"
+	EventTradeManageOrderSettlement EventType = "trade_manage_order_settlement"
+)
+
+// Message 小程序推送的客服消息与事件，JSON 与 XML 格式解析为同一结构
+// 不同 MsgType / Event 使用的字段不同，未使用的字段为零值。
+type Message struct {
+	XMLName      xml.Name `xml:"xml" json:"-"`
+	ToUserName   string   `xml:"ToUserName" json:"ToUserName"`
+	FromUserName string   `xml:"FromUserName" json:"FromUserName"`
+	CreateTime   int64    `xml:"CreateTime" json:"CreateTime"`
+	MsgType      MsgType  `xml:"MsgType" json:"MsgType"`
+	MsgID        int64    `xml:"MsgId" json:"MsgId"`
+
+	// 文本消息
+	Content string `xml:"Content" json:"Content"`
+
+	// 图片消息
+	PicURL  string `xml:"PicUrl" json:"PicUrl"`
+	MediaID string `xml:"MediaId" json:"MediaId"`
+
+	// 小程序卡片消息
+	Title        string `xml:"Title" json:"Title"`
+	PageAppID    string `xml:"AppId" json:"AppId"`
+	PagePath     string `xml:"PagePath" json:"PagePath"`
+	ThumbURL     string `xml:"ThumbUrl" json:"ThumbUrl"`
+	ThumbMediaID string `xml:"ThumbMediaId" json:"ThumbMediaId"`
+
+	// 事件
+	Event EventType `xml:"Event" json:"Event"`
+	// SessionFrom 进入客服会话事件（user_enter_tempsession）中 button 的 session-from 属性
+	SessionFrom string `xml:"SessionFrom" json:"SessionFrom"`
+
+	// SubscribeMsgEvents 订阅消息弹框、用户改变订阅状态、订阅消息发送结果事件的明细
+	SubscribeMsgEvents []SubscribeMsgEvent `xml:"-" json:"-"`
+
+	// 音视频内容安全异步检测结果事件（wxa_media_check）
+	AppID        string             `xml:"appid" json:"appid"`
+	TraceID      string             `xml:"trace_id" json:"trace_id"`
+	Version      int                `xml:"version" json:"version"`
+	ErrCode      int                `xml:"errcode" json:"errcode"`
+	ErrMsg       string             `xml:"errmsg" json:"errmsg"`
+	MediaResult  *MediaCheckResult  `xml:"result" json:"result"`
+	MediaDetails []MediaCheckDetail `xml:"detail" json:"detail"`
+
+	// 小程序发货信息管理事件（trade_manage_*）
+	TradeMsg                string `xml:"msg" json:"msg"`
+	TransactionID           string `xml:"transaction_id" json:"transaction_id"`
+	MerchantID              string `xml:"merchant_id" json:"merchant_id"`
+	SubMerchantID           string `xml:"sub_merchant_id" json:"sub_merchant_id"`
+	MerchantTradeNo         string `xml:"merchant_trade_no" json:"merchant_trade_no"`
+	PayTime                 int64  `xml:"pay_time" json:"pay_time"`
+	ShippedTime             int64  `xml:"shipped_time" json:"shipped_time"`
+	EstimatedSettlementTime int64  `xml:"estimated_settlement_time" json:"estimated_settlement_time"`
+	ConfirmReceiveMethod    int    `xml:"confirm_receive_method" json:"confirm_receive_method"`
+	ConfirmReceiveTime      int64  `xml:"confirm_receive_time" json:"confirm_receive_time"`
+	SettlementTime          int64  `xml:"settlement_time" json:"settlement_time"`
+}
+
+// IsEvent 是否为事件推送
+func (m *Message) IsEvent() bool {
+	return m.MsgType == MsgTypeEvent
+}
+
+// SubscribeMsgEvent 订阅消息事件明细
+// 弹框与改变订阅状态事件使用 SubscribeStatusString / PopupScene，发送结果事件使用 MsgID / ErrorCode / ErrorStatus。
+type SubscribeMsgEvent struct {
+	TemplateID            string `xml:"TemplateId"`
+	SubscribeStatusString string `xml:"SubscribeStatusString"`
+	PopupScene            string `xml:"PopupScene"`
+	MsgID                 string `xml:"MsgID"`
+	ErrorCode             int    `xml:"ErrorCode"`
+	ErrorStatus           string `xml:"ErrorStatus"`
+}
+
+// UnmarshalJSON 兼容 JSON 推送中数字字段以字符串下发的情况
+func (e *SubscribeMsgEvent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TemplateID            string      `json:"TemplateId"`
+		SubscribeStatusString string      `json:"SubscribeStatusString"`
+		PopupScene            json.Number `json:"PopupScene"`
+		MsgID                 json.Number `json:"MsgID"`
+		ErrorCode             json.Number `json:"ErrorCode"`
+		ErrorStatus           string      `json:"ErrorStatus"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var errorCode int64
+	if raw.ErrorCode != "" {
+		var err error
+		if errorCode, err = raw.ErrorCode.Int64(); err != nil {
+			return fmt.Errorf("parse ErrorCode: %w", err)
+		}
+	}
+
+	*e = SubscribeMsgEvent{
+		TemplateID:            raw.TemplateID,
+		SubscribeStatusString: raw.SubscribeStatusString,
+		PopupScene:            raw.PopupScene.String(),
+		MsgID:                 raw.MsgID.String(),
+		ErrorCode:             int(errorCode),
+		ErrorStatus:           raw.ErrorStatus,
+	}
+	return nil
+}
+
+// MediaCheckResult 音视频内容安全检测综合结果
+type MediaCheckResult struct {
+	Suggest string `xml:"suggest" json:"suggest"`
+	Label   int    `xml:"label" json:"label"`
+}
+
+// MediaCheckDetail 音视频内容安全检测详细结果
+type MediaCheckDetail struct {
+	Strategy string `xml:"strategy" json:"strategy"`
+	ErrCode  int    `xml:"errcode" json:"errcode"`
+	Suggest  string `xml:"suggest" json:"suggest"`
+	Label    int    `xml:"label" json:"label"`
+	Keyword  string `xml:"keyword" json:"keyword"`
+	Prob     int    `xml:"prob" json:"prob"`
+}
+
+// ParseXMLMessage 解析 XML 格式的明文推送
+func ParseXMLMessage(data []byte) (*Message, error) {
+	var raw struct {
+		Message
+		SubscribeMsgPopup  []SubscribeMsgEvent `xml:"SubscribeMsgPopupEvent>List"`
+		SubscribeMsgChange []SubscribeMsgEvent `xml:"SubscribeMsgChangeEvent>List"`
+		SubscribeMsgSent   []SubscribeMsgEvent `xml:"SubscribeMsgSentEvent>List"`
+	}
+	if err := xml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal xml message: %w", err)
+	}
+
+	msg := raw.Message
+	switch msg.Event {
+	case EventSubscribeMsgPopup:
+		msg.SubscribeMsgEvents = raw.SubscribeMsgPopup
+	case EventSubscribeMsgChange:
+		msg.SubscribeMsgEvents = raw.SubscribeMsgChange
+	case EventSubscribeMsgSent:
+		msg.SubscribeMsgEvents = raw.SubscribeMsgSent
+	}
+	return &msg, nil
+}
+
+// ParseJSONMessage 解析 JSON 格式的明文推送
+func ParseJSONMessage(data []byte) (*Message, error) {
+	// 别名类型避免递归；CreateTime 在部分事件中以字符串下发
+	type message Message
+	var raw struct {
+		message
+		CreateTime json.Number     `json:"CreateTime"`
+		List       json.RawMessage `json:"List"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal json message: %w", err)
+	}
+
+	msg := Message(raw.message)
+	if raw.CreateTime != "" {
+		createTime, err := raw.CreateTime.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("parse CreateTime: %w", err)
+		}
+		msg.CreateTime = createTime
+	}
+
+	// 订阅消息事件的 List 可能是数组，也可能是单个对象
+	list := bytes.TrimSpace(raw.List)
+	switch {
+	case len(list) == 0 || bytes.Equal(list, []byte("null")):
+	case list[0] == '[':
+		if err := json.Unmarshal(list, &msg.SubscribeMsgEvents); err != nil {
+			return nil, fmt.Errorf("unmarshal List: %w", err)
+		}
+	default:
+		var event SubscribeMsgEvent
+		if err := json.Unmarshal(list, &event); err != nil {
+			return nil, fmt.Errorf("unmarshal List: %w", err)
+		}
+		msg.SubscribeMsgEvents = []SubscribeMsgEvent{event}
+	}
+	return &msg, nil
+}
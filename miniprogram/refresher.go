@@ -0,0 +1,12 @@
+package miniprogram
+
+import "github.com/ShinyNito/FunkWechat/v2/core"
+
+// NewRefresher 创建后台刷新器，注册 access_token 的续期任务。
+// 返回的刷新器需调用 Start 启动、Stop 停止。
+func (c *Client) NewRefresher(cfg core.RefresherConfig) *core.Refresher {
+	if cfg.Logger == nil {
+		cfg.Logger = c.cfg.Logger
+	}
+	return core.NewRefresher(cfg).Add("access_token", c.tokenManager.Renew)
+}
@@ -0,0 +1,62 @@
+package miniprogram
+
+import "context"
+
+// MessageHandler 消息处理函数
+// 小程序消息推送不支持被动回复，处理完成后统一回复 success。
+type MessageHandler func(ctx context.Context, msg *Message) error
+
+type routeKey struct {
+	msgType MsgType
+	event   EventType
+}
+
+// Router 按 MsgType / Event 分发消息
+// 匹配优先级：Event > MsgType > Default。
+type Router struct {
+	routes   map[routeKey]MessageHandler
+	fallback MessageHandler
+}
+
+// NewRouter 创建消息路由
+func NewRouter() *Router {
+	return &Router{routes: make(map[routeKey]MessageHandler)}
+}
+
+// Message 注册客服消息处理函数
+func (r *Router) Message(msgType MsgType, h MessageHandler) *Router {
+	r.routes[routeKey{msgType: msgType}] = h
+	return r
+}
+
+// Event 注册事件处理函数
+func (r *Router) Event(event EventType, h MessageHandler) *Router {
+	r.routes[routeKey{msgType: MsgTypeEvent, event: event}] = h
+	return r
+}
+
+// Default 注册兜底处理函数
+func (r *Router) Default(h MessageHandler) *Router {
+	r.fallback = h
+	return r
+}
+
+// Dispatch 分发消息，没有匹配的处理函数时直接返回
+func (r *Router) Dispatch(ctx context.Context, msg *Message) error {
+	if h := r.match(msg); h != nil {
+		return h(ctx, msg)
+	}
+	return nil
+}
+
+func (r *Router) match(msg *Message) MessageHandler {
+	if msg.IsEvent() {
+		if h, ok := r.routes[routeKey{msgType: MsgTypeEvent, event: msg.Event}]; ok {
+			return h
+		}
+	}
+	if h, ok := r.routes[routeKey{msgType: msg.MsgType}]; ok {
+		return h
+	}
+	return r.fallback
+}
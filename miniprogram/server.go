@@ -0,0 +1,150 @@
+package miniprogram
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ShinyNito/FunkWechat/v2/core/utils"
+)
+
+const (
+	defaultMaxCallbackBodySize = 1 << 20
+	callbackSuccess            = "success"
+)
+
+// ServerConfig 消息推送服务配置
+type ServerConfig struct {
+	// Token 小程序后台「消息推送」中填写的 Token
+	Token string
+	// EncodingAESKey 消息加解密密钥，配置后支持安全模式与兼容模式（encrypt_type=aes）
+	EncodingAESKey string
+	// Router 消息路由
+	Router *Router
+	// MaxBodySize 请求体大小上限，<= 0 时使用默认值 1MB
+	MaxBodySize int64
+}
+
+// Server 小程序消息推送服务，实现 http.Handler
+// GET 请求用于服务器地址校验（原样返回 echostr），POST 请求按内容自动识别 JSON / XML 格式，
+// 必要时解密后分发到 Router，并回复 success。
+type Server struct {
+	token       string
+	router      *Router
+	crypto      *utils.MsgCrypto
+	maxBodySize int64
+	logger      *slog.Logger
+}
+
+// NewServer 创建消息推送服务
+func (c *Client) NewServer(cfg ServerConfig) (*Server, error) {
+	if strings.TrimSpace(cfg.Token) == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+	if cfg.Router == nil {
+		return nil, fmt.Errorf("router is required")
+	}
+
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxCallbackBodySize
+	}
+
+	var crypto *utils.MsgCrypto
+	if cfg.EncodingAESKey != "" {
+		var err error
+		crypto, err = utils.NewMsgCrypto(cfg.Token, cfg.EncodingAESKey, c.cfg.AppID)
+		if err != nil {
+			return nil, fmt.Errorf("init message crypto: %w", err)
+		}
+	}
+
+	return &Server{
+		token:       cfg.Token,
+		router:      cfg.Router,
+		crypto:      crypto,
+		maxBodySize: maxBodySize,
+		logger:      c.cfg.Logger,
+	}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !utils.VerifySignature(query.Get("signature"), query.Get("timestamp"), query.Get("nonce"), s.token) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_, _ = io.WriteString(w, query.Get("echostr"))
+	case http.MethodPost:
+		s.handleMessage(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, s.maxBodySize+1))
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > s.maxBodySize {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	isJSON := isJSONBody(body)
+	query := r.URL.Query()
+	if query.Get("encrypt_type") == "aes" {
+		if s.crypto == nil {
+			http.Error(w, "encoding aes key not configured", http.StatusBadRequest)
+			return
+		}
+		decrypt := s.crypto.DecryptXML
+		if isJSON {
+			decrypt = s.crypto.DecryptJSON
+		}
+		body, err = decrypt(query.Get("msg_signature"), query.Get("timestamp"), query.Get("nonce"), body)
+		if err != nil {
+			s.logger.WarnContext(ctx, "decrypt message failed", slog.Any("error", err))
+			http.Error(w, "decrypt message failed", http.StatusBadRequest)
+			return
+		}
+	}
+
+	parse := ParseXMLMessage
+	if isJSON {
+		parse = ParseJSONMessage
+	}
+	msg, err := parse(body)
+	if err != nil {
+		s.logger.WarnContext(ctx, "parse message failed", slog.Any("error", err))
+		http.Error(w, "invalid message", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.router.Dispatch(ctx, msg); err != nil {
+		// 回复 success 避免微信重试
+		s.logger.ErrorContext(ctx, "handle message failed",
+			slog.String("msg_type", string(msg.MsgType)),
+			slog.String("event", string(msg.Event)),
+			slog.Any("error", err),
+		)
+	}
+	_, _ = io.WriteString(w, callbackSuccess)
+}
+
+// isJSONBody 根据首个非空白字符区分 JSON 与 XML 格式
+func isJSONBody(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
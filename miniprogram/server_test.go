@@ -0,0 +1,180 @@
+package miniprogram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ShinyNito/FunkWechat/v2/core/utils"
+)
+
+const (
+	testCallbackToken = "callback-token"
+	testAESKey        = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+)
+
+func newTestServer(t *testing.T, router *Router, aesKey string) *Server {
+	t.Helper()
+	client, err := New(Config{AppID: "appid", AppSecret: "secret"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	server, err := client.NewServer(ServerConfig{Token: testCallbackToken, Router: router, EncodingAESKey: aesKey})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	return server
+}
+
+func signedQuery(extra url.Values) url.Values {
+	q := url.Values{}
+	q.Set("timestamp", "1700000000")
+	q.Set("nonce", "nonce")
+	q.Set("signature", utils.SHA1Sign(testCallbackToken, "1700000000", "nonce"))
+	for k, v := range extra {
+		q[k] = v
+	}
+	return q
+}
+
+func serveCallback(server *Server, method string, query url.Values, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/wechat?"+query.Encode(), strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerVerifyURL(t *testing.T) {
+	server := newTestServer(t, NewRouter(), "")
+
+	rec := serveCallback(server, http.MethodGet, signedQuery(url.Values{"echostr": {"echo-1"}}), "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "echo-1" {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	query := signedQuery(nil)
+	query.Set("signature", "bad")
+	rec = serveCallback(server, http.MethodPost, query, `{}`)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestServerJSONAndXML(t *testing.T) {
+	var got []*Message
+	record := func(ctx context.Context, msg *Message) error {
+		got = append(got, msg)
+		return nil
+	}
+	router := NewRouter().
+		Message(MsgTypeText, record).
+		Event(EventUserEnterTempSession, record).
+		Event(EventSubscribeMsgPopup, record)
+	server := newTestServer(t, router, "")
+
+	bodies := []string{
+		`{"ToUserName":"gh_1","FromUserName":"openid-1","CreateTime":1700000000,"MsgType":"text","Content":"hi","MsgId":42}`,
+		`<xml><ToUserName><![CDATA[gh_1]]></ToUserName><MsgType><![CDATA[event]]></MsgType>` +
+			`<Event><![CDATA[user_enter_tempsession]]></Event><SessionFrom><![CDATA[from-button]]></SessionFrom></xml>`,
+		`{"ToUserName":"gh_1","CreateTime":"1700000001","MsgType":"event","Event":"subscribe_msg_popup_event",` +
+			`"List":[{"TemplateId":"tpl-1","SubscribeStatusString":"accept","PopupScene":"0"}]}`,
+	}
+	for _, body := range bodies {
+		rec := serveCallback(server, http.MethodPost, signedQuery(nil), body)
+		if rec.Code != http.StatusOK || rec.Body.String() != callbackSuccess {
+			t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 dispatched messages, got %d", len(got))
+	}
+	if got[0].Content != "hi" || got[0].MsgID != 42 {
+		t.Fatalf("unexpected text message: %+v", got[0])
+	}
+	if got[1].SessionFrom != "from-button" {
+		t.Fatalf("unexpected session from: %q", got[1].SessionFrom)
+	}
+	if got[2].CreateTime != 1700000001 || len(got[2].SubscribeMsgEvents) != 1 || got[2].SubscribeMsgEvents[0].SubscribeStatusString != "accept" {
+		t.Fatalf("unexpected popup event: %+v", got[2])
+	}
+}
+
+func TestServerEncryptedJSON(t *testing.T) {
+	var got *Message
+	router := NewRouter().Event(EventMediaCheck, func(ctx context.Context, msg *Message) error {
+		got = msg
+		return nil
+	})
+	server := newTestServer(t, router, testAESKey)
+
+	crypto, err := utils.NewMsgCrypto(testCallbackToken, testAESKey, "appid")
+	if err != nil {
+		t.Fatalf("new crypto: %v", err)
+	}
+	plain := `{"ToUserName":"gh_1","MsgType":"event","Event":"wxa_media_check","appid":"wx1","trace_id":"trace-1",` +
+		`"version":2,"errcode":0,"errmsg":"ok","result":{"suggest":"risky","label":20001},"detail":[{"strategy":"content_model","errcode":0,"suggest":"risky","label":20001,"prob":90}]}`
+	encrypted, err := crypto.Encrypt([]byte(plain))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	query := signedQuery(url.Values{
+		"encrypt_type":  {"aes"},
+		"msg_signature": {crypto.Signature("1700000000", "nonce", encrypted)},
+	})
+
+	rec := serveCallback(server, http.MethodPost, query, `{"ToUserName":"gh_1","Encrypt":"`+encrypted+`"}`)
+	if rec.Code != http.StatusOK || rec.Body.String() != callbackSuccess {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if got == nil || got.TraceID != "trace-1" || got.MediaResult == nil || got.MediaResult.Suggest != "risky" || len(got.MediaDetails) != 1 {
+		t.Fatalf("unexpected media check event: %+v", got)
+	}
+
+	query.Set("msg_signature", "bad")
+	rec = serveCallback(server, http.MethodPost, query, `{"ToUserName":"gh_1","Encrypt":"`+encrypted+`"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestServerHandlerError(t *testing.T) {
+	server := newTestServer(t, NewRouter().Default(func(ctx context.Context, msg *Message) error {
+		return errors.New("boom")
+	}), "")
+
+	rec := serveCallback(server, http.MethodPost, signedQuery(nil), `<xml><MsgType>text</MsgType></xml>`)
+	if rec.Code != http.StatusOK || rec.Body.String() != callbackSuccess {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveCallback(server, http.MethodPost, signedQuery(nil), `{not json`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestParseXMLSubscribeMsgSent(t *testing.T) {
+	body := `<xml><MsgType>event</MsgType><Event>subscribe_msg_sent_event</Event>
+<SubscribeMsgSentEvent><List><TemplateId>tpl-1</TemplateId><MsgID>1700</MsgID><ErrorCode>0</ErrorCode><ErrorStatus>success</ErrorStatus></List></SubscribeMsgSentEvent></xml>`
+	msg, err := ParseXMLMessage([]byte(body))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(msg.SubscribeMsgEvents) != 1 || msg.SubscribeMsgEvents[0].MsgID != "1700" || msg.SubscribeMsgEvents[0].ErrorStatus != "success" {
+		t.Fatalf("unexpected events: %+v", msg.SubscribeMsgEvents)
+	}
+
+	msg, err = ParseJSONMessage([]byte(`{"MsgType":"event","Event":"subscribe_msg_sent_event",` +
+		`"List":{"TemplateId":"tpl-1","MsgID":"1700","ErrorCode":"20004","ErrorStatus":"fail"}}`))
+	if err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if len(msg.SubscribeMsgEvents) != 1 || msg.SubscribeMsgEvents[0].ErrorCode != 20004 {
+		t.Fatalf("unexpected events: %+v", msg.SubscribeMsgEvents)
+	}
+}
@@ -0,0 +1,375 @@
+package miniprogram
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	subscribeSendPath             = "/cgi-bin/message/subscribe/send"
+	subscribeGetCategoryPath      = "/wxaapi/newtmpl/getcategory"
+	subscribeGetPubTitlesPath     = "/wxaapi/newtmpl/getpubtemplatetitles"
+	subscribeGetPubKeywordsPath   = "/wxaapi/newtmpl/getpubtemplatekeywords"
+	subscribeAddTemplatePath      = "/wxaapi/newtmpl/addtemplate"
+	subscribeDeleteTemplatePath   = "/wxaapi/newtmpl/deltemplate"
+	subscribeGetTemplateListPath  = "/wxaapi/newtmpl/gettemplate"
+	maxPubTemplateTitlesPageLimit = 30
+)
+
+// MiniprogramState 点击订阅消息后跳转的小程序版本
+type MiniprogramState string
+
+const (
+	MiniprogramStateDeveloper MiniprogramState = "developer"
+	MiniprogramStateTrial     MiniprogramState = "trial"
+	MiniprogramStateFormal    MiniprogramState = "formal"
+)
+
+// SubscribeDataItem 订阅消息中单个关键词的内容
+type SubscribeDataItem struct {
+	Value string `json:"value"`
+}
+
+// SubscribeData 订阅消息数据，key 为模板中的关键词名称（如 thing1、time2）
+// 关键词名称去掉末尾数字即为关键词类型，发送前按类型校验内容格式。
+type SubscribeData map[string]SubscribeDataItem
+
+// NewSubscribeData 创建订阅消息数据
+func NewSubscribeData() SubscribeData {
+	return make(SubscribeData)
+}
+
+// Set 设置关键词内容
+func (d SubscribeData) Set(key, value string) SubscribeData {
+	d[key] = SubscribeDataItem{Value: value}
+	return d
+}
+
+// Validate 按关键词类型校验内容格式，未知类型只校验非空
+func (d SubscribeData) Validate() error {
+	if len(d) == 0 {
+		return fmt.Errorf("data is required")
+	}
+	for _, key := range slices.Sorted(maps.Keys(d)) {
+		kind := strings.TrimRightFunc(key, unicode.IsDigit)
+		if err := validateSubscribeValue(kind, d[key].Value); err != nil {
+			return fmt.Errorf("data.%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+var (
+	subscribeNumberPattern     = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+	subscribeLetterPattern     = regexp.MustCompile(`^[A-Za-z]+$`)
+	subscribeCharStringPattern = regexp.MustCompile(`^[\x20-\x7e]+$`)
+	subscribeAmountPattern     = regexp.MustCompile(`^\p{Sc}?(\d+(?:\.\d+)?)元?$`)
+	subscribePhonePattern      = regexp.MustCompile(`^[\d+\-]+$`)
+	subscribeCarNumberPattern  = regexp.MustCompile(`^\p{Han}?[A-Za-z0-9]+\p{Han}?$`)
+	subscribePhrasePattern     = regexp.MustCompile(`^\p{Han}+$`)
+	subscribeDatePattern       = regexp.MustCompile(`^(\d{4}年\d{1,2}月\d{1,2}日|\d{4}[-/.]\d{1,2}[-/.]\d{1,2})$`)
+	subscribeClockPattern      = regexp.MustCompile(`^\d{1,2}:\d{2}(:\d{2})?$`)
+)
+
+// validateSubscribeValue 校验订阅消息关键词格式，规则见微信订阅消息文档「订阅消息参数值内容限制说明」
+func validateSubscribeValue(kind, value string) error {
+	if value == "" {
+		return fmt.Errorf("value is required")
+	}
+
+	runes := utf8.RuneCountInString(value)
+	switch kind {
+	case "thing":
+		return checkRuneLimit(value, runes, 20)
+	case "short_thing":
+		return checkRuneLimit(value, runes, 5)
+	case "number":
+		if !subscribeNumberPattern.MatchString(value) {
+			return fmt.Errorf("number must be numeric, got %q", value)
+		}
+		return checkRuneLimit(value, runes, 32)
+	case "letter":
+		if !subscribeLetterPattern.MatchString(value) {
+			return fmt.Errorf("letter must contain only letters, got %q", value)
+		}
+		return checkRuneLimit(value, runes, 32)
+	case "symbol":
+		if strings.IndexFunc(value, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			return fmt.Errorf("symbol must contain only symbols, got %q", value)
+		}
+		return checkRuneLimit(value, runes, 5)
+	case "character_string":
+		if !subscribeCharStringPattern.MatchString(value) {
+			return fmt.Errorf("character_string must contain only digits, letters or symbols, got %q", value)
+		}
+		return checkRuneLimit(value, runes, 32)
+	case "time":
+		return validateSubscribeDateTime(value, false)
+	case "date":
+		return validateSubscribeDateTime(value, true)
+	case "amount":
+		m := subscribeAmountPattern.FindStringSubmatch(value)
+		if m == nil {
+			return fmt.Errorf("amount must be an optional currency symbol followed by a number, got %q", value)
+		}
+		if digits := len(strings.ReplaceAll(m[1], ".", "")); digits > 10 {
+			return fmt.Errorf("amount allows at most 10 digits, got %d", digits)
+		}
+		return nil
+	case "phone_number":
+		if !subscribePhonePattern.MatchString(value) {
+			return fmt.Errorf("phone_number must contain only digits, '+' or '-', got %q", value)
+		}
+		return checkRuneLimit(value, runes, 17)
+	case "car_number":
+		if !subscribeCarNumberPattern.MatchString(value) {
+			return fmt.Errorf("invalid car_number %q", value)
+		}
+		return checkRuneLimit(value, runes, 8)
+	case "name":
+		if subscribeLetterPattern.MatchString(strings.ReplaceAll(value, " ", "")) {
+			return checkRuneLimit(value, runes, 20)
+		}
+		return checkRuneLimit(value, runes, 10)
+	case "phrase":
+		if !subscribePhrasePattern.MatchString(value) {
+			return fmt.Errorf("phrase must contain only Chinese characters, got %q", value)
+		}
+		return checkRuneLimit(value, runes, 5)
+	}
+	return nil
+}
+
+func checkRuneLimit(value string, runes, limit int) error {
+	if runes > limit {
+		return fmt.Errorf("value exceeds %d characters: %q", limit, value)
+	}
+	return nil
+}
+
+// validateSubscribeDateTime 校验 time / date 类型：支持「年月日」与「时:分」组合，时间段用 ~ 连接。
+// date 类型必须包含日期，time 类型必须包含时间。
+func validateSubscribeDateTime(value string, requireDate bool) error {
+	for part := range strings.SplitSeq(value, "~") {
+		fields := strings.Fields(part)
+		var hasDate, hasClock bool
+		switch len(fields) {
+		case 1:
+			hasDate = subscribeDatePattern.MatchString(fields[0])
+			hasClock = !hasDate && subscribeClockPattern.MatchString(fields[0])
+		case 2:
+			hasDate = subscribeDatePattern.MatchString(fields[0])
+			hasClock = subscribeClockPattern.MatchString(fields[1])
+			if !hasDate || !hasClock {
+				return fmt.Errorf("invalid date time %q", part)
+			}
+		default:
+			return fmt.Errorf("invalid date time %q", part)
+		}
+
+		if requireDate && !hasDate {
+			return fmt.Errorf("date requires a date component, got %q", part)
+		}
+		if !requireDate && !hasClock {
+			return fmt.Errorf("time requires a 24-hour clock component, got %q", part)
+		}
+	}
+	return nil
+}
+
+type SendSubscribeMessageRequest struct {
+	ToUser     string `json:"touser"`
+	TemplateID string `json:"template_id"`
+	// Page 点击消息后跳转的小程序页面，可带参数，不填则不跳转
+	Page string        `json:"page,omitempty"`
+	Data SubscribeData `json:"data"`
+	// MiniprogramState 跳转的小程序版本，默认为正式版
+	MiniprogramState MiniprogramState `json:"miniprogram_state,omitempty"`
+	// Lang 进入小程序查看的语言类型：zh_CN、en_US、zh_HK、zh_TW，默认为 zh_CN
+	Lang string `json:"lang,omitempty"`
+}
+
+type SubscribeCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type GetSubscribeCategoryResponse struct {
+	Data []SubscribeCategory `json:"data"`
+}
+
+type GetPubTemplateTitlesRequest struct {
+	// IDs 类目 ID，多个类目的模板会一起返回
+	IDs   []int
+	Start int
+	// Limit 每页数量，最大 30
+	Limit int
+}
+
+type PubTemplateTitle struct {
+	TID        int    `json:"tid"`
+	Title      string `json:"title"`
+	Type       int    `json:"type"`
+	CategoryID string `json:"categoryId"`
+}
+
+type GetPubTemplateTitlesResponse struct {
+	Count int                `json:"count"`
+	Data  []PubTemplateTitle `json:"data"`
+}
+
+type GetPubTemplateKeywordsRequest struct {
+	TID string
+}
+
+type PubTemplateKeyword struct {
+	KID     int    `json:"kid"`
+	Name    string `json:"name"`
+	Example string `json:"example"`
+	// Rule 关键词类型，如 thing、time、number
+	Rule string `json:"rule"`
+}
+
+type GetPubTemplateKeywordsResponse struct {
+	Count int                  `json:"count"`
+	Data  []PubTemplateKeyword `json:"data"`
+}
+
+type AddSubscribeTemplateRequest struct {
+	TID       string `json:"tid"`
+	KIDList   []int  `json:"kidList"`
+	SceneDesc string `json:"sceneDesc,omitempty"`
+}
+
+type AddSubscribeTemplateResponse struct {
+	PriTmplID string `json:"priTmplId"`
+}
+
+type DeleteSubscribeTemplateRequest struct {
+	PriTmplID string `json:"priTmplId"`
+}
+
+type SubscribeTemplate struct {
+	PriTmplID            string                   `json:"priTmplId"`
+	Title                string                   `json:"title"`
+	Content              string                   `json:"content"`
+	Example              string                   `json:"example"`
+	Type                 int                      `json:"type"`
+	KeywordEnumValueList []SubscribeKeywordValues `json:"keywordEnumValueList"`
+}
+
+type SubscribeKeywordValues struct {
+	KeywordCode   string   `json:"keywordCode"`
+	EnumValueList []string `json:"enumValueList"`
+}
+
+type GetSubscribeTemplateListResponse struct {
+	Data []SubscribeTemplate `json:"data"`
+}
+
+// SendSubscribeMessage 发送订阅消息，发送前按关键词类型校验 Data
+func (c *Client) SendSubscribeMessage(ctx context.Context, req SendSubscribeMessageRequest) error {
+	if req.ToUser == "" {
+		return fmt.Errorf("touser is required")
+	}
+	if req.TemplateID == "" {
+		return fmt.Errorf("template_id is required")
+	}
+	switch req.MiniprogramState {
+	case "", MiniprogramStateDeveloper, MiniprogramStateTrial, MiniprogramStateFormal:
+	default:
+		return fmt.Errorf("invalid miniprogram_state: %s", req.MiniprogramState)
+	}
+	if err := req.Data.Validate(); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(subscribeSendPath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// GetSubscribeCategory 获取小程序账号的类目
+func (c *Client) GetSubscribeCategory(ctx context.Context) (GetSubscribeCategoryResponse, error) {
+	return Request[GetSubscribeCategoryResponse](c).
+		Path(subscribeGetCategoryPath).
+		Get(ctx)
+}
+
+// GetPubTemplateTitles 获取所属类目下的公共模板标题
+func (c *Client) GetPubTemplateTitles(ctx context.Context, req GetPubTemplateTitlesRequest) (GetPubTemplateTitlesResponse, error) {
+	if len(req.IDs) == 0 {
+		return GetPubTemplateTitlesResponse{}, fmt.Errorf("ids is required")
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxPubTemplateTitlesPageLimit {
+		limit = maxPubTemplateTitlesPageLimit
+	}
+
+	ids := make([]string, len(req.IDs))
+	for i, id := range req.IDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	return Request[GetPubTemplateTitlesResponse](c).
+		Path(subscribeGetPubTitlesPath).
+		Query("ids", strings.Join(ids, ",")).
+		Query("start", strconv.Itoa(req.Start)).
+		Query("limit", strconv.Itoa(limit)).
+		Get(ctx)
+}
+
+// GetPubTemplateKeywords 获取公共模板的关键词列表
+func (c *Client) GetPubTemplateKeywords(ctx context.Context, req GetPubTemplateKeywordsRequest) (GetPubTemplateKeywordsResponse, error) {
+	if req.TID == "" {
+		return GetPubTemplateKeywordsResponse{}, fmt.Errorf("tid is required")
+	}
+
+	return Request[GetPubTemplateKeywordsResponse](c).
+		Path(subscribeGetPubKeywordsPath).
+		Query("tid", req.TID).
+		Get(ctx)
+}
+
+// AddSubscribeTemplate 从公共模板库选用模板到私有模板库，返回私有模板 ID
+func (c *Client) AddSubscribeTemplate(ctx context.Context, req AddSubscribeTemplateRequest) (AddSubscribeTemplateResponse, error) {
+	if req.TID == "" {
+		return AddSubscribeTemplateResponse{}, fmt.Errorf("tid is required")
+	}
+	if len(req.KIDList) < 2 || len(req.KIDList) > 5 {
+		return AddSubscribeTemplateResponse{}, fmt.Errorf("kidList requires 2 to 5 keywords, got %d", len(req.KIDList))
+	}
+
+	return Request[AddSubscribeTemplateResponse](c).
+		Path(subscribeAddTemplatePath).
+		Body(req).
+		Post(ctx)
+}
+
+// DeleteSubscribeTemplate 删除私有模板库中的模板
+func (c *Client) DeleteSubscribeTemplate(ctx context.Context, req DeleteSubscribeTemplateRequest) error {
+	if req.PriTmplID == "" {
+		return fmt.Errorf("priTmplId is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(subscribeDeleteTemplatePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// GetSubscribeTemplateList 获取私有模板列表
+func (c *Client) GetSubscribeTemplateList(ctx context.Context) (GetSubscribeTemplateListResponse, error) {
+	return Request[GetSubscribeTemplateListResponse](c).
+		Path(subscribeGetTemplateListPath).
+		Get(ctx)
+}
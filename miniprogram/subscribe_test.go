@@ -0,0 +1,152 @@
+package miniprogram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSubscribeDataValidate(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{key: "thing1", value: "一二三四五六七八九十一二三四五六七八九十"},
+		{key: "thing1", value: "一二三四五六七八九十一二三四五六七八九十一", wantErr: true},
+		{key: "number2", value: "-12.50"},
+		{key: "number2", value: "12a", wantErr: true},
+		{key: "letter3", value: "ABC"},
+		{key: "letter3", value: "AB1", wantErr: true},
+		{key: "symbol4", value: "%+"},
+		{key: "symbol4", value: "%a", wantErr: true},
+		{key: "character_string5", value: "ORDER-2026_001"},
+		{key: "character_string5", value: "订单001", wantErr: true},
+		{key: "time6", value: "15:01"},
+		{key: "time6", value: "2019年10月1日 15:01~2019年10月1日 16:00"},
+		{key: "time6", value: "2019年10月1日", wantErr: true},
+		{key: "date7", value: "2019年10月1日"},
+		{key: "date7", value: "2019-10-01 15:01"},
+		{key: "date7", value: "15:01", wantErr: true},
+		{key: "amount8", value: "¥100.50元"},
+		{key: "amount8", value: "12345678901", wantErr: true},
+		{key: "phone_number9", value: "+86-0766-66888866"},
+		{key: "phone_number9", value: "+86-0766-668888669999", wantErr: true},
+		{key: "car_number10", value: "粤A8Z888挂"},
+		{key: "car_number10", value: "粤A-8Z888", wantErr: true},
+		{key: "name11", value: "张三"},
+		{key: "name11", value: "Alexander Hamilton"},
+		{key: "name11", value: "一二三四五六七八九十一", wantErr: true},
+		{key: "phrase12", value: "配送中"},
+		{key: "phrase12", value: "ok", wantErr: true},
+		{key: "unknown13", value: "anything"},
+		{key: "thing14", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := NewSubscribeData().Set(tt.key, tt.value).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wantErr=%v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "data."+tt.key+":") {
+				t.Fatalf("error should name the keyword: %v", err)
+			}
+		})
+	}
+
+	if err := NewSubscribeData().Validate(); err == nil {
+		t.Fatal("expected empty data error")
+	}
+}
+
+func TestSendSubscribeMessage(t *testing.T) {
+	calls := 0
+	var got map[string]any
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != subscribeSendPath {
+			http.NotFound(w, r)
+			return
+		}
+		calls++
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	ctx := context.Background()
+
+	err := client.SendSubscribeMessage(ctx, SendSubscribeMessageRequest{
+		ToUser:           "openid-1",
+		TemplateID:       "tpl-1",
+		Page:             "pages/order?id=1",
+		Data:             NewSubscribeData().Set("thing1", "订单已发货").Set("time2", "2026年1月1日 10:00"),
+		MiniprogramState: MiniprogramStateTrial,
+	})
+	if err != nil {
+		t.Fatalf("send subscribe message: %v", err)
+	}
+	if got["miniprogram_state"] != "trial" || got["data"].(map[string]any)["thing1"].(map[string]any)["value"] != "订单已发货" {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+
+	err = client.SendSubscribeMessage(ctx, SendSubscribeMessageRequest{
+		ToUser:     "openid-1",
+		TemplateID: "tpl-1",
+		Data:       NewSubscribeData().Set("phrase1", "shipped"),
+	})
+	if err == nil {
+		t.Fatal("expected data validation error")
+	}
+	if calls != 1 {
+		t.Fatalf("invalid data should not be sent, got %d calls", calls)
+	}
+}
+
+func TestSubscribeTemplateLibrary(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case subscribeGetCategoryPath:
+			_, _ = w.Write([]byte(`{"errcode":0,"data":[{"id":616,"name":"公交"}]}`))
+		case subscribeGetPubTitlesPath:
+			q := r.URL.Query()
+			if q.Get("ids") != "2,616" || q.Get("start") != "0" || q.Get("limit") != "30" {
+				t.Errorf("unexpected query: %v", q)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"count":1,"data":[{"tid":99,"title":"付款成功通知","type":2,"categoryId":"616"}]}`))
+		case subscribeGetPubKeywordsPath:
+			_, _ = w.Write([]byte(`{"errcode":0,"count":1,"data":[{"kid":1,"name":"物品名称","example":"名称","rule":"thing"}]}`))
+		case subscribeAddTemplatePath:
+			_, _ = w.Write([]byte(`{"errcode":0,"priTmplId":"pri-1"}`))
+		case subscribeGetTemplateListPath:
+			_, _ = w.Write([]byte(`{"errcode":0,"data":[{"priTmplId":"pri-1","title":"付款成功通知","type":2}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	categories, err := client.GetSubscribeCategory(ctx)
+	if err != nil || categories.Data[0].ID != 616 {
+		t.Fatalf("get category: %+v %v", categories, err)
+	}
+	titles, err := client.GetPubTemplateTitles(ctx, GetPubTemplateTitlesRequest{IDs: []int{2, 616}})
+	if err != nil || titles.Data[0].TID != 99 {
+		t.Fatalf("get titles: %+v %v", titles, err)
+	}
+	keywords, err := client.GetPubTemplateKeywords(ctx, GetPubTemplateKeywordsRequest{TID: "99"})
+	if err != nil || keywords.Data[0].Rule != "thing" {
+		t.Fatalf("get keywords: %+v %v", keywords, err)
+	}
+	added, err := client.AddSubscribeTemplate(ctx, AddSubscribeTemplateRequest{TID: "99", KIDList: []int{1, 2}})
+	if err != nil || added.PriTmplID != "pri-1" {
+		t.Fatalf("add template: %+v %v", added, err)
+	}
+	if _, err := client.AddSubscribeTemplate(ctx, AddSubscribeTemplateRequest{TID: "99", KIDList: []int{1}}); err == nil {
+		t.Fatal("expected kidList validation error")
+	}
+	list, err := client.GetSubscribeTemplateList(ctx)
+	if err != nil || list.Data[0].PriTmplID != "pri-1" {
+		t.Fatalf("get template list: %+v %v", list, err)
+	}
+}
@@ -0,0 +1,173 @@
+package miniprogram
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+const (
+	wxaCodeUnlimitPath = "/wxa/getwxacodeunlimit"
+	wxaCodePath        = "/wxa/getwxacode"
+	wxaQRCodePath      = "/cgi-bin/wxaapp/createwxaqrcode"
+	maxWxaCodeScene    = 32
+	maxWxaCodePath     = 1024
+	maxWxaQRCodePath   = 128
+)
+
+// EnvVersion 要打开的小程序版本
+type EnvVersion string
+
+const (
+	EnvVersionRelease EnvVersion = "release"
+	EnvVersionTrial   EnvVersion = "trial"
+	EnvVersionDevelop EnvVersion = "develop"
+)
+
+// LineColor 小程序码线条颜色，AutoColor 为 false 时生效
+type LineColor struct {
+	R int `json:"r"`
+	G int `json:"g"`
+	B int `json:"b"`
+}
+
+// Image 图片内容，ContentType 通常为 image/jpeg 或 image/png
+type Image struct {
+	ContentType string
+	Data        []byte
+}
+
+type GetWxaCodeUnlimitRequest struct {
+	// Scene 最大 32 个可见字符，小程序中通过 options.scene 获取
+	Scene string `json:"scene"`
+	// Page 已发布小程序的页面，不能带参数，默认为主页
+	Page string `json:"page,omitempty"`
+	// CheckPath 为 false 时不校验页面是否存在，可用于未发布的页面；默认校验
+	CheckPath  *bool      `json:"check_path,omitempty"`
+	EnvVersion EnvVersion `json:"env_version,omitempty"`
+	Width      int        `json:"width,omitempty"`
+	AutoColor  bool       `json:"auto_color,omitempty"`
+	LineColor  *LineColor `json:"line_color,omitempty"`
+	IsHyaline  bool       `json:"is_hyaline,omitempty"`
+}
+
+type GetWxaCodeRequest struct {
+	// Path 页面路径，可带参数，最大 1024 字节
+	Path       string     `json:"path"`
+	EnvVersion EnvVersion `json:"env_version,omitempty"`
+	Width      int        `json:"width,omitempty"`
+	AutoColor  bool       `json:"auto_color,omitempty"`
+	LineColor  *LineColor `json:"line_color,omitempty"`
+	IsHyaline  bool       `json:"is_hyaline,omitempty"`
+}
+
+type CreateWxaQRCodeRequest struct {
+	// Path 页面路径，可带参数，最大 128 字节
+	Path  string `json:"path"`
+	Width int    `json:"width,omitempty"`
+}
+
+// GetWxaCodeUnlimit 获取不限数量的小程序码
+func (c *Client) GetWxaCodeUnlimit(ctx context.Context, req GetWxaCodeUnlimitRequest) (Image, error) {
+	if err := validateWxaCodeUnlimit(req); err != nil {
+		return Image{}, err
+	}
+	return c.fetchImage(ctx, wxaCodeUnlimitPath, req)
+}
+
+// GetWxaCodeUnlimitTo 获取不限数量的小程序码并写入 w，返回图片的 Content-Type
+func (c *Client) GetWxaCodeUnlimitTo(ctx context.Context, req GetWxaCodeUnlimitRequest, w io.Writer) (string, error) {
+	if err := validateWxaCodeUnlimit(req); err != nil {
+		return "", err
+	}
+	return c.writeImage(ctx, wxaCodeUnlimitPath, req, w)
+}
+
+// GetWxaCode 获取小程序码，与 CreateWxaQRCode 合计总数量有限
+func (c *Client) GetWxaCode(ctx context.Context, req GetWxaCodeRequest) (Image, error) {
+	if err := validateWxaCodePath(req.Path, maxWxaCodePath); err != nil {
+		return Image{}, err
+	}
+	return c.fetchImage(ctx, wxaCodePath, req)
+}
+
+// GetWxaCodeTo 获取小程序码并写入 w，返回图片的 Content-Type
+func (c *Client) GetWxaCodeTo(ctx context.Context, req GetWxaCodeRequest, w io.Writer) (string, error) {
+	if err := validateWxaCodePath(req.Path, maxWxaCodePath); err != nil {
+		return "", err
+	}
+	return c.writeImage(ctx, wxaCodePath, req, w)
+}
+
+// CreateWxaQRCode 获取小程序二维码，与 GetWxaCode 合计总数量有限
+func (c *Client) CreateWxaQRCode(ctx context.Context, req CreateWxaQRCodeRequest) (Image, error) {
+	if err := validateWxaCodePath(req.Path, maxWxaQRCodePath); err != nil {
+		return Image{}, err
+	}
+	return c.fetchImage(ctx, wxaQRCodePath, req)
+}
+
+// CreateWxaQRCodeTo 获取小程序二维码并写入 w，返回图片的 Content-Type
+func (c *Client) CreateWxaQRCodeTo(ctx context.Context, req CreateWxaQRCodeRequest, w io.Writer) (string, error) {
+	if err := validateWxaCodePath(req.Path, maxWxaQRCodePath); err != nil {
+		return "", err
+	}
+	return c.writeImage(ctx, wxaQRCodePath, req, w)
+}
+
+func (c *Client) fetchImage(ctx context.Context, path string, body any) (Image, error) {
+	// 生成接口没有副作用，瞬时失败可安全重试
+	resp, err := c.apiClient.Request().
+		Path(path).
+		Body(body).
+		Idempotent(true).
+		Post(ctx)
+	if err != nil {
+		return Image{}, err
+	}
+
+	bin, err := core.DecodeBinary(resp)
+	if err != nil {
+		return Image{}, err
+	}
+	return Image{ContentType: bin.ContentType, Data: bin.Body}, nil
+}
+
+func (c *Client) writeImage(ctx context.Context, path string, body any, w io.Writer) (string, error) {
+	resp, err := c.apiClient.Request().
+		Path(path).
+		Body(body).
+		Idempotent(true).
+		PostStream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return "", fmt.Errorf("write image: %w", err)
+	}
+	return resp.ContentType, nil
+}
+
+func validateWxaCodeUnlimit(req GetWxaCodeUnlimitRequest) error {
+	if req.Scene == "" {
+		return fmt.Errorf("scene is required")
+	}
+	if len(req.Scene) > maxWxaCodeScene {
+		return fmt.Errorf("scene exceeds %d characters", maxWxaCodeScene)
+	}
+	return nil
+}
+
+func validateWxaCodePath(path string, maxBytes int) error {
+	if path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if len(path) > maxBytes {
+		return fmt.Errorf("path exceeds %d bytes", maxBytes)
+	}
+	return nil
+}
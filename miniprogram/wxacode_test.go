@@ -0,0 +1,96 @@
+package miniprogram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+var testPNG = []byte("\x89PNG\r\n\x1a\nimage-data")
+
+func TestGetWxaCodeUnlimit(t *testing.T) {
+	var got map[string]any
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case wxaCodeUnlimitPath:
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			if got["scene"] == "bad" {
+				w.Header().Set("Content-Type", "application/json; encoding=utf-8")
+				_, _ = w.Write([]byte(`{"errcode":41030,"errmsg":"invalid page"}`))
+				return
+			}
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(testPNG)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	checkPath := false
+	img, err := client.GetWxaCodeUnlimit(ctx, GetWxaCodeUnlimitRequest{
+		Scene:      "id=1",
+		Page:       "pages/index/index",
+		CheckPath:  &checkPath,
+		EnvVersion: EnvVersionTrial,
+		LineColor:  &LineColor{R: 1, G: 2, B: 3},
+	})
+	if err != nil {
+		t.Fatalf("get wxacode unlimit: %v", err)
+	}
+	if img.ContentType != "image/png" || !bytes.Equal(img.Data, testPNG) {
+		t.Fatalf("unexpected image: %s %q", img.ContentType, img.Data)
+	}
+	if got["check_path"] != false || got["env_version"] != "trial" {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+
+	var buf bytes.Buffer
+	contentType, err := client.GetWxaCodeUnlimitTo(ctx, GetWxaCodeUnlimitRequest{Scene: "id=2"}, &buf)
+	if err != nil || contentType != "image/png" || !bytes.Equal(buf.Bytes(), testPNG) {
+		t.Fatalf("unexpected streamed image: %s %v", contentType, err)
+	}
+
+	_, err = client.GetWxaCodeUnlimit(ctx, GetWxaCodeUnlimitRequest{Scene: "bad"})
+	var we *core.WechatError
+	if !errors.As(err, &we) || we.ErrCode != 41030 {
+		t.Fatalf("expected WechatError 41030, got %v", err)
+	}
+
+	if _, err := client.GetWxaCodeUnlimit(ctx, GetWxaCodeUnlimitRequest{Scene: "0123456789012345678901234567890123"}); err == nil {
+		t.Fatal("expected scene length validation error")
+	}
+}
+
+func TestGetWxaCodeAndQRCode(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case wxaCodePath:
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = w.Write([]byte("jpeg-data"))
+		case wxaQRCodePath:
+			// 不带 Content-Type 的错误响应按内容识别为 JSON
+			w.Header()["Content-Type"] = nil
+			_, _ = w.Write([]byte(`{"errcode":45029,"errmsg":"qrcode count out of limit"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	img, err := client.GetWxaCode(ctx, GetWxaCodeRequest{Path: "pages/index?id=1"})
+	if err != nil || img.ContentType != "image/jpeg" || string(img.Data) != "jpeg-data" {
+		t.Fatalf("get wxacode: %+v %v", img, err)
+	}
+
+	_, err = client.CreateWxaQRCode(ctx, CreateWxaQRCodeRequest{Path: "pages/index"})
+	var we *core.WechatError
+	if !errors.As(err, &we) || we.ErrCode != 45029 {
+		t.Fatalf("expected WechatError 45029, got %v", err)
+	}
+}
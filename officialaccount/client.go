@@ -1,7 +1,7 @@
 package officialaccount
 
 import (
-	"context"
+	"cmp"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,32 +12,39 @@ import (
 )
 
 const (
-	accessTokenPath           = "/cgi-bin/token"
+	accessTokenPath           = core.AccessTokenPath
+	defaultMPBaseURL          = "https://mp.weixin.qq.com"
 	accessTokenCacheKeyPrefix = "officialaccount:access_token:"
 	tokenExpireBuffer         = 300
 )
 
 type Config struct {
-	AppID      string
-	AppSecret  string
-	Cache      core.Cache
-	HTTPClient *http.Client
-	Logger     *slog.Logger
-	BaseURL    string
+	AppID       string
+	AppSecret   string
+	Cache       core.Cache
+	HTTPClient  *http.Client
+	Logger      *slog.Logger
+	BaseURL     string
+	TokenRetry  core.TokenRetryPolicy
+	Middlewares []core.Middleware
+	Retry       core.RetryPolicy
+	// StableToken 为 true 时使用 /cgi-bin/stable_token 获取 access_token，
+	// 多个环境共用同一 AppID 时不会互相挤掉 token
+	StableToken bool
+	// Locker 多实例部署时用于跨进程去重 token 刷新，通常为 core.NewCacheLocker(共享 Cache)
+	Locker core.Locker
+	// MPBaseURL mp.weixin.qq.com 域名下接口（如二维码图片下载）的地址，为空时使用 https://mp.weixin.qq.com
+	MPBaseURL string
 }
 
 type Client struct {
 	cfg          Config
 	apiClient    *core.Client
+	mpClient     *core.Client
 	tokenManager *core.TokenManager
 	ticketMu     sync.Mutex
 }
 
-type accessTokenResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int    `json:"expires_in"`
-}
-
 func New(cfg Config) (*Client, error) {
 	cfg = normalizeConfig(cfg)
 	if err := validateConfig(cfg); err != nil {
@@ -45,32 +52,25 @@ func New(cfg Config) (*Client, error) {
 	}
 
 	tokenClient, err := core.NewClient(core.ClientConfig{
-		BaseURL:    cfg.BaseURL,
-		HTTPClient: cfg.HTTPClient,
-		Logger:     cfg.Logger,
+		BaseURL:     cfg.BaseURL,
+		HTTPClient:  cfg.HTTPClient,
+		Logger:      cfg.Logger,
+		Middlewares: cfg.Middlewares,
+		Retry:       cfg.Retry,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	fetcher, refreshFetcher := newTokenFetchers(cfg, tokenClient)
 	tokenManager, err := core.NewTokenManager(core.TokenManagerConfig{
 		Cache:               cfg.Cache,
 		CacheKey:            accessTokenCacheKeyPrefix + cfg.AppID,
 		ExpireBufferSeconds: tokenExpireBuffer,
 		Logger:              cfg.Logger,
-		Fetcher: func(ctx context.Context) (core.TokenFetchResult, error) {
-			resp, err := core.NewTypedRequest[accessTokenResponse](tokenClient).
-				Path(accessTokenPath).
-				Query("grant_type", "client_credential").
-				Query("appid", cfg.AppID).
-				Query("secret", cfg.AppSecret).
-				WithoutToken().
-				Get(ctx)
-			if err != nil {
-				return core.TokenFetchResult{}, fmt.Errorf("request access token: %w", err)
-			}
-			return core.TokenFetchResult{Token: resp.AccessToken, ExpiresIn: resp.ExpiresIn}, nil
-		},
+		Fetcher:             fetcher,
+		RefreshFetcher:      refreshFetcher,
+		Locker:              cfg.Locker,
 	})
 	if err != nil {
 		return nil, err
@@ -81,12 +81,26 @@ func New(cfg Config) (*Client, error) {
 		HTTPClient:    cfg.HTTPClient,
 		TokenProvider: tokenManager,
 		Logger:        cfg.Logger,
+		TokenRetry:    cfg.TokenRetry,
+		Middlewares:   cfg.Middlewares,
+		Retry:         cfg.Retry,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mpClient, err := core.NewClient(core.ClientConfig{
+		BaseURL:     cmp.Or(cfg.MPBaseURL, defaultMPBaseURL),
+		HTTPClient:  cfg.HTTPClient,
+		Logger:      cfg.Logger,
+		Middlewares: cfg.Middlewares,
+		Retry:       cfg.Retry,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{cfg: cfg, apiClient: apiClient, tokenManager: tokenManager}, nil
+	return &Client{cfg: cfg, apiClient: apiClient, mpClient: mpClient, tokenManager: tokenManager}, nil
 }
 
 func (c *Client) Config() Config {
@@ -97,6 +111,15 @@ func (c *Client) AccessTokenProvider() core.AccessTokenProvider {
 	return c.tokenManager
 }
 
+func newTokenFetchers(cfg Config, tokenClient *core.Client) (core.TokenFetcher, core.TokenFetcher) {
+	if cfg.StableToken {
+		stable := core.NewStableTokenFetcher(tokenClient, cfg.AppID, cfg.AppSecret)
+		return stable.Fetch, stable.ForceFetch
+	}
+	fetcher := core.NewAccessTokenFetcher(tokenClient, cfg.AppID, cfg.AppSecret)
+	return fetcher, fetcher
+}
+
 func normalizeConfig(cfg Config) Config {
 	if cfg.Cache == nil {
 		cfg.Cache = core.NewMemoryCache()
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -194,3 +195,111 @@ func TestGetTicket_InvalidCachedValueWillRefresh(t *testing.T) {
 		t.Fatalf("expected 1 ticket call, got %d", ticketCalls)
 	}
 }
+
+func TestStableToken(t *testing.T) {
+	var forceFlags []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case accessTokenPath:
+			t.Fatal("legacy token endpoint should not be used")
+		case core.StableAccessTokenPath:
+			var body struct {
+				ForceRefresh bool `json:"force_refresh"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			forceFlags = append(forceFlags, body.ForceRefresh)
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "stable-1", "expires_in": 7200})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL, StableToken: true})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	token, err := client.AccessTokenProvider().GetToken(context.Background())
+	if err != nil || token != "stable-1" {
+		t.Fatalf("get token: %s, %v", token, err)
+	}
+	if _, err := client.AccessTokenProvider().RefreshToken(context.Background()); err != nil {
+		t.Fatalf("refresh token: %v", err)
+	}
+	if len(forceFlags) != 2 || forceFlags[0] || !forceFlags[1] {
+		t.Fatalf("unexpected force flags: %v", forceFlags)
+	}
+}
+
+func TestNewRefresher(t *testing.T) {
+	var tokenCalls, ticketCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case accessTokenPath:
+			tokenCalls.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token-1", "expires_in": 7200})
+		case getTicketPath:
+			ticketCalls.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errcode": 0, "ticket": "ticket-" + r.URL.Query().Get("type"), "expires_in": 7200})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	refresher := client.NewRefresher(core.RefresherConfig{})
+	if err := refresher.Start(context.Background()); err != nil {
+		t.Fatalf("start refresher: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for ticketCalls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	refresher.Stop()
+
+	if got := ticketCalls.Load(); got != 2 {
+		t.Fatalf("expected jsapi and wx_card tickets to be refreshed, got %d calls", got)
+	}
+
+	ticket, err := client.GetTicket(context.Background(), GetTicketRequest{Type: TicketTypeWxCard})
+	if err != nil {
+		t.Fatalf("get ticket: %v", err)
+	}
+	if ticket != "ticket-wx_card" {
+		t.Fatalf("unexpected ticket: %s", ticket)
+	}
+	if got := ticketCalls.Load(); got != 2 {
+		t.Fatalf("get ticket should hit cache, got %d calls", got)
+	}
+	if got := tokenCalls.Load(); got != 1 {
+		t.Fatalf("expected 1 token call, got %d", got)
+	}
+}
+
+// newTestAPIClient 创建指向测试服务的客户端，access_token 请求由测试服务自动应答，其余请求交给 handler
+func newTestAPIClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == accessTokenPath {
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token-1", "expires_in": 7200})
+			return
+		}
+		if got := r.URL.Query().Get("access_token"); got != "token-1" {
+			t.Errorf("unexpected access_token: %q", got)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
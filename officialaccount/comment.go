@@ -0,0 +1,233 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+const (
+	commentOpenPath        = "/cgi-bin/comment/open"
+	commentClosePath       = "/cgi-bin/comment/close"
+	commentListPath        = "/cgi-bin/comment/list"
+	commentMarkElectPath   = "/cgi-bin/comment/markelect"
+	commentUnmarkElectPath = "/cgi-bin/comment/unmarkelect"
+	commentDeletePath      = "/cgi-bin/comment/delete"
+	commentReplyAddPath    = "/cgi-bin/comment/reply/add"
+	commentReplyDeletePath = "/cgi-bin/comment/reply/delete"
+	maxCommentListCount    = 49
+)
+
+// CommentListType 留言列表的筛选类型
+type CommentListType int
+
+const (
+	CommentListAll     CommentListType = 0
+	CommentListNormal  CommentListType = 1
+	CommentListElected CommentListType = 2
+)
+
+// CommentType 留言是否被精选
+type CommentType int
+
+const (
+	CommentTypeNormal  CommentType = 0
+	CommentTypeElected CommentType = 1
+)
+
+// ArticleRef 定位群发图文中的一篇文章
+type ArticleRef struct {
+	// MsgDataID 群发返回的 msg_data_id
+	MsgDataID int64 `json:"msg_data_id"`
+	// Index 多图文中的第几篇，从 0 开始
+	Index int `json:"index"`
+}
+
+// CommentRef 定位文章中的一条留言
+type CommentRef struct {
+	MsgDataID     int64 `json:"msg_data_id"`
+	Index         int   `json:"index"`
+	UserCommentID int64 `json:"user_comment_id"`
+}
+
+// Comment 用户留言
+type Comment struct {
+	UserCommentID int64       `json:"user_comment_id"`
+	OpenID        string      `json:"openid"`
+	CreateTime    int64       `json:"create_time"`
+	Content       string      `json:"content"`
+	CommentType   CommentType `json:"comment_type"`
+	// Reply 作者回复，未回复时为 nil
+	Reply *CommentReply `json:"reply,omitempty"`
+}
+
+// IsElected 是否为精选留言
+func (c Comment) IsElected() bool {
+	return c.CommentType == CommentTypeElected
+}
+
+// CommentReply 作者对留言的回复
+type CommentReply struct {
+	Content    string `json:"content"`
+	CreateTime int64  `json:"create_time"`
+}
+
+type ListCommentsRequest struct {
+	MsgDataID int64 `json:"msg_data_id"`
+	Index     int   `json:"index"`
+	// Begin 起始位置
+	Begin int `json:"begin"`
+	// Count 获取数目，微信拒绝 >= 50 的取值，因此取值 1~49，<= 0 或超出时使用 49
+	Count int             `json:"count"`
+	Type  CommentListType `json:"type"`
+}
+
+type ListCommentsResponse struct {
+	Total   int       `json:"total"`
+	Comment []Comment `json:"comment"`
+}
+
+type AddCommentReplyRequest struct {
+	MsgDataID     int64  `json:"msg_data_id"`
+	Index         int    `json:"index"`
+	UserCommentID int64  `json:"user_comment_id"`
+	Content       string `json:"content"`
+}
+
+// OpenComment 打开文章的留言功能
+func (c *Client) OpenComment(ctx context.Context, req ArticleRef) error {
+	if req.MsgDataID == 0 {
+		return fmt.Errorf("msg_data_id is required")
+	}
+	return c.postComment(ctx, commentOpenPath, req)
+}
+
+// CloseComment 关闭文章的留言功能
+func (c *Client) CloseComment(ctx context.Context, req ArticleRef) error {
+	if req.MsgDataID == 0 {
+		return fmt.Errorf("msg_data_id is required")
+	}
+	return c.postComment(ctx, commentClosePath, req)
+}
+
+// ListComments 获取文章的一页留言
+func (c *Client) ListComments(ctx context.Context, req ListCommentsRequest) (ListCommentsResponse, error) {
+	req, err := normalizeListComments(req)
+	if err != nil {
+		return ListCommentsResponse{}, err
+	}
+
+	return Request[ListCommentsResponse](c).
+		Path(commentListPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// Comments 从 Begin 开始遍历文章的全部留言，自动翻页。
+// 请求失败时产出一次非 nil 错误并结束遍历。
+func (c *Client) Comments(ctx context.Context, req ListCommentsRequest) iter.Seq2[Comment, error] {
+	return func(yield func(Comment, error) bool) {
+		req, err := normalizeListComments(req)
+		if err != nil {
+			yield(Comment{}, err)
+			return
+		}
+		for {
+			page, err := c.ListComments(ctx, req)
+			if err != nil {
+				yield(Comment{}, err)
+				return
+			}
+			for _, comment := range page.Comment {
+				if !yield(comment, nil) {
+					return
+				}
+			}
+			req.Begin += len(page.Comment)
+			if len(page.Comment) == 0 || req.Begin >= page.Total {
+				return
+			}
+		}
+	}
+}
+
+// MarkElectComment 将留言标记为精选
+func (c *Client) MarkElectComment(ctx context.Context, req CommentRef) error {
+	if err := validateCommentRef(req); err != nil {
+		return err
+	}
+	return c.postComment(ctx, commentMarkElectPath, req)
+}
+
+// UnmarkElectComment 取消留言的精选
+func (c *Client) UnmarkElectComment(ctx context.Context, req CommentRef) error {
+	if err := validateCommentRef(req); err != nil {
+		return err
+	}
+	return c.postComment(ctx, commentUnmarkElectPath, req)
+}
+
+// DeleteComment 删除留言
+func (c *Client) DeleteComment(ctx context.Context, req CommentRef) error {
+	if err := validateCommentRef(req); err != nil {
+		return err
+	}
+	return c.postComment(ctx, commentDeletePath, req)
+}
+
+// AddCommentReply 回复留言，每条留言只能有一条作者回复
+func (c *Client) AddCommentReply(ctx context.Context, req AddCommentReplyRequest) error {
+	if err := validateCommentRef(CommentRef{MsgDataID: req.MsgDataID, Index: req.Index, UserCommentID: req.UserCommentID}); err != nil {
+		return err
+	}
+	if req.Content == "" {
+		return fmt.Errorf("content is required")
+	}
+	return c.postComment(ctx, commentReplyAddPath, req)
+}
+
+// DeleteCommentReply 删除作者对留言的回复
+func (c *Client) DeleteCommentReply(ctx context.Context, req CommentRef) error {
+	if err := validateCommentRef(req); err != nil {
+		return err
+	}
+	return c.postComment(ctx, commentReplyDeletePath, req)
+}
+
+// postComment 发送只返回 errcode 的留言管理请求
+func (c *Client) postComment(ctx context.Context, path string, body any) error {
+	_, err := Request[struct{}](c).
+		Path(path).
+		Body(body).
+		Post(ctx)
+	return err
+}
+
+func normalizeListComments(req ListCommentsRequest) (ListCommentsRequest, error) {
+	if req.MsgDataID == 0 {
+		return req, fmt.Errorf("msg_data_id is required")
+	}
+	switch req.Type {
+	case CommentListAll, CommentListNormal, CommentListElected:
+	default:
+		return req, fmt.Errorf("invalid comment list type: %d", req.Type)
+	}
+	if req.Begin < 0 {
+		req.Begin = 0
+	}
+	if req.Count <= 0 || req.Count > maxCommentListCount {
+		req.Count = maxCommentListCount
+	}
+	return req, nil
+}
+
+func validateCommentRef(req CommentRef) error {
+	if req.MsgDataID == 0 {
+		return fmt.Errorf("msg_data_id is required")
+	}
+	if req.UserCommentID == 0 {
+		return fmt.Errorf("user_comment_id is required")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCommentsIterator(t *testing.T) {
+	var begins []int
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ListCommentsRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != commentListPath || req.MsgDataID != 2247483650 || req.Index != 1 || req.Count != 2 || req.Type != CommentListElected {
+			t.Errorf("unexpected list request: %s %+v", r.URL.Path, req)
+		}
+		begins = append(begins, req.Begin)
+		var comments []string
+		for i := req.Begin; i < min(req.Begin+req.Count, 3); i++ {
+			comments = append(comments, fmt.Sprintf(`{"user_comment_id":%d,"openid":"o%d","create_time":1700000000,"content":"c%d","comment_type":1}`, i+1, i, i))
+		}
+		if req.Begin == 0 {
+			comments[0] = `{"user_comment_id":1,"openid":"o0","create_time":1700000000,"content":"c0","comment_type":1,"reply":{"content":"thanks","create_time":1700000100}}`
+		}
+		_, _ = fmt.Fprintf(w, `{"errcode":0,"errmsg":"ok","total":3,"comment":[%s]}`, strings.Join(comments, ","))
+	})
+
+	var got []Comment
+	for comment, err := range client.Comments(context.Background(), ListCommentsRequest{MsgDataID: 2247483650, Index: 1, Count: 2, Type: CommentListElected}) {
+		if err != nil {
+			t.Fatalf("iterate comments: %v", err)
+		}
+		got = append(got, comment)
+	}
+	if len(got) != 3 || len(begins) != 2 || begins[1] != 2 {
+		t.Fatalf("unexpected paging: %d comments, begins %v", len(got), begins)
+	}
+	if got[0].Reply == nil || got[0].Reply.Content != "thanks" || got[1].Reply != nil || !got[2].IsElected() || got[2].OpenID != "o2" {
+		t.Fatalf("unexpected comments: %+v", got)
+	}
+
+	for _, err := range client.Comments(context.Background(), ListCommentsRequest{}) {
+		if err == nil {
+			t.Fatal("expected msg_data_id validation error")
+		}
+	}
+}
+
+func TestListCommentsDefaultCount(t *testing.T) {
+	var counts []int
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req ListCommentsRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		counts = append(counts, req.Count)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","total":0,"comment":[]}`))
+	})
+
+	for _, count := range []int{0, 50, 100} {
+		if _, err := client.ListComments(context.Background(), ListCommentsRequest{MsgDataID: 2247483650, Count: count}); err != nil {
+			t.Fatalf("list comments: %v", err)
+		}
+	}
+	if len(counts) != 3 || counts[0] != 49 || counts[1] != 49 || counts[2] != 49 {
+		t.Fatalf("count must stay below 50, got %v", counts)
+	}
+}
+
+func TestCommentManagement(t *testing.T) {
+	var paths []string
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.Path)
+		if body["msg_data_id"] != float64(2247483650) {
+			t.Errorf("unexpected body: %v", body)
+		}
+		if r.URL.Path == commentReplyAddPath && (body["content"] != "thanks" || body["user_comment_id"] != float64(7)) {
+			t.Errorf("unexpected reply body: %v", body)
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	ctx := context.Background()
+	article := ArticleRef{MsgDataID: 2247483650}
+	comment := CommentRef{MsgDataID: 2247483650, UserCommentID: 7}
+
+	calls := []error{
+		client.OpenComment(ctx, article),
+		client.MarkElectComment(ctx, comment),
+		client.AddCommentReply(ctx, AddCommentReplyRequest{MsgDataID: 2247483650, UserCommentID: 7, Content: "thanks"}),
+		client.DeleteCommentReply(ctx, comment),
+		client.UnmarkElectComment(ctx, comment),
+		client.DeleteComment(ctx, comment),
+		client.CloseComment(ctx, article),
+	}
+	for i, err := range calls {
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	want := []string{commentOpenPath, commentMarkElectPath, commentReplyAddPath, commentReplyDeletePath, commentUnmarkElectPath, commentDeletePath, commentClosePath}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+
+	if err := client.DeleteComment(ctx, CommentRef{MsgDataID: 2247483650}); err == nil {
+		t.Fatal("expected user_comment_id validation error")
+	}
+	if err := client.AddCommentReply(ctx, AddCommentReplyRequest{MsgDataID: 2247483650, UserCommentID: 7}); err == nil {
+		t.Fatal("expected content validation error")
+	}
+}
@@ -0,0 +1,266 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	customSendPath    = "/cgi-bin/message/custom/send"
+	customTypingPath  = "/cgi-bin/message/custom/typing"
+	maxCustomNewsSize = 1
+)
+
+// CustomMessage 客服消息内容
+// 由 CustomText、CustomImage、CustomVoice、CustomVideo、CustomMusic、CustomNews、
+// CustomMPNews、CustomMsgMenu、CustomWxCard、CustomMiniprogramPage 实现。
+type CustomMessage interface {
+	apply(body *customMessageBody) error
+}
+
+// CustomText 文本消息，可包含 <a href="..."> 或 data-miniprogram-appid 跳转链接
+type CustomText struct {
+	Content string `json:"content"`
+}
+
+// CustomImage 图片消息
+type CustomImage struct {
+	MediaID string `json:"media_id"`
+}
+
+// CustomVoice 语音消息
+type CustomVoice struct {
+	MediaID string `json:"media_id"`
+}
+
+// CustomVideo 视频消息
+type CustomVideo struct {
+	MediaID      string `json:"media_id"`
+	ThumbMediaID string `json:"thumb_media_id"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// CustomMusic 音乐消息
+type CustomMusic struct {
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	MusicURL     string `json:"musicurl"`
+	HQMusicURL   string `json:"hqmusicurl"`
+	ThumbMediaID string `json:"thumb_media_id"`
+}
+
+// CustomNews 外链图文消息，微信限制最多 1 条图文
+type CustomNews struct {
+	Articles []CustomNewsArticle `json:"articles"`
+}
+
+// CustomNewsArticle 外链图文消息中的单条图文
+type CustomNewsArticle struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	PicURL      string `json:"picurl,omitempty"`
+}
+
+// CustomMPNews 图文素材消息
+type CustomMPNews struct {
+	MediaID string `json:"media_id"`
+}
+
+// CustomMsgMenu 菜单消息，用户点击菜单后推送 Content 为菜单内容、bizmsgmenuid 为菜单 ID 的文本消息
+type CustomMsgMenu struct {
+	HeadContent string        `json:"head_content,omitempty"`
+	List        []MsgMenuItem `json:"list"`
+	TailContent string        `json:"tail_content,omitempty"`
+}
+
+// MsgMenuItem 菜单消息中的单个选项
+type MsgMenuItem struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// CustomWxCard 卡券消息
+type CustomWxCard struct {
+	CardID string `json:"card_id"`
+}
+
+// CustomMiniprogramPage 小程序卡片消息
+type CustomMiniprogramPage struct {
+	Title        string `json:"title"`
+	AppID        string `json:"appid"`
+	PagePath     string `json:"pagepath"`
+	ThumbMediaID string `json:"thumb_media_id"`
+}
+
+// TypingCommand 客服输入状态
+type TypingCommand string
+
+const (
+	TypingCommandTyping       TypingCommand = "Typing"
+	TypingCommandCancelTyping TypingCommand = "CancelTyping"
+)
+
+type SendCustomMessageRequest struct {
+	ToUser  string
+	Message CustomMessage
+	// KfAccount 以指定客服账号的身份发送，为空时使用公众号身份
+	KfAccount string
+}
+
+type SetTypingRequest struct {
+	ToUser  string        `json:"touser"`
+	Command TypingCommand `json:"command"`
+}
+
+type customService struct {
+	KfAccount string `json:"kf_account"`
+}
+
+type customMessageBody struct {
+	ToUser          string                 `json:"touser"`
+	MsgType         string                 `json:"msgtype"`
+	Text            *CustomText            `json:"text,omitempty"`
+	Image           *CustomImage           `json:"image,omitempty"`
+	Voice           *CustomVoice           `json:"voice,omitempty"`
+	Video           *CustomVideo           `json:"video,omitempty"`
+	Music           *CustomMusic           `json:"music,omitempty"`
+	News            *CustomNews            `json:"news,omitempty"`
+	MPNews          *CustomMPNews          `json:"mpnews,omitempty"`
+	MsgMenu         *CustomMsgMenu         `json:"msgmenu,omitempty"`
+	WxCard          *CustomWxCard          `json:"wxcard,omitempty"`
+	MiniprogramPage *CustomMiniprogramPage `json:"miniprogrampage,omitempty"`
+	CustomService   *customService         `json:"customservice,omitempty"`
+}
+
+func (m CustomText) apply(body *customMessageBody) error {
+	if m.Content == "" {
+		return fmt.Errorf("text content is required")
+	}
+	body.MsgType, body.Text = "text", &m
+	return nil
+}
+
+func (m CustomImage) apply(body *customMessageBody) error {
+	if m.MediaID == "" {
+		return fmt.Errorf("image media_id is required")
+	}
+	body.MsgType, body.Image = "image", &m
+	return nil
+}
+
+func (m CustomVoice) apply(body *customMessageBody) error {
+	if m.MediaID == "" {
+		return fmt.Errorf("voice media_id is required")
+	}
+	body.MsgType, body.Voice = "voice", &m
+	return nil
+}
+
+func (m CustomVideo) apply(body *customMessageBody) error {
+	if m.MediaID == "" || m.ThumbMediaID == "" {
+		return fmt.Errorf("video media_id and thumb_media_id are required")
+	}
+	body.MsgType, body.Video = "video", &m
+	return nil
+}
+
+func (m CustomMusic) apply(body *customMessageBody) error {
+	if m.MusicURL == "" || m.ThumbMediaID == "" {
+		return fmt.Errorf("music musicurl and thumb_media_id are required")
+	}
+	body.MsgType, body.Music = "music", &m
+	return nil
+}
+
+func (m CustomNews) apply(body *customMessageBody) error {
+	if len(m.Articles) == 0 {
+		return fmt.Errorf("news articles are required")
+	}
+	if len(m.Articles) > maxCustomNewsSize {
+		return fmt.Errorf("news exceeds %d article", maxCustomNewsSize)
+	}
+	body.MsgType, body.News = "news", &m
+	return nil
+}
+
+func (m CustomMPNews) apply(body *customMessageBody) error {
+	if m.MediaID == "" {
+		return fmt.Errorf("mpnews media_id is required")
+	}
+	body.MsgType, body.MPNews = "mpnews", &m
+	return nil
+}
+
+func (m CustomMsgMenu) apply(body *customMessageBody) error {
+	if len(m.List) == 0 {
+		return fmt.Errorf("msgmenu list is required")
+	}
+	for i, item := range m.List {
+		if item.ID == "" || item.Content == "" {
+			return fmt.Errorf("msgmenu list[%d]: id and content are required", i)
+		}
+	}
+	body.MsgType, body.MsgMenu = "msgmenu", &m
+	return nil
+}
+
+func (m CustomWxCard) apply(body *customMessageBody) error {
+	if m.CardID == "" {
+		return fmt.Errorf("wxcard card_id is required")
+	}
+	body.MsgType, body.WxCard = "wxcard", &m
+	return nil
+}
+
+func (m CustomMiniprogramPage) apply(body *customMessageBody) error {
+	if m.AppID == "" || m.PagePath == "" || m.ThumbMediaID == "" {
+		return fmt.Errorf("miniprogrampage appid, pagepath and thumb_media_id are required")
+	}
+	body.MsgType, body.MiniprogramPage = "miniprogrampage", &m
+	return nil
+}
+
+// SendCustomMessage 发送客服消息，用户 48 小时内与公众号有过互动时才能下发
+func (c *Client) SendCustomMessage(ctx context.Context, req SendCustomMessageRequest) error {
+	if req.ToUser == "" {
+		return fmt.Errorf("touser is required")
+	}
+	if req.Message == nil {
+		return fmt.Errorf("message is required")
+	}
+
+	body := customMessageBody{ToUser: req.ToUser}
+	if err := req.Message.apply(&body); err != nil {
+		return err
+	}
+	if req.KfAccount != "" {
+		body.CustomService = &customService{KfAccount: req.KfAccount}
+	}
+
+	_, err := Request[struct{}](c).
+		Path(customSendPath).
+		Body(body).
+		Post(ctx)
+	return err
+}
+
+// SetTyping 下发或取消客服输入状态，Typing 状态持续 15 秒
+func (c *Client) SetTyping(ctx context.Context, req SetTypingRequest) error {
+	if req.ToUser == "" {
+		return fmt.Errorf("touser is required")
+	}
+	switch req.Command {
+	case TypingCommandTyping, TypingCommandCancelTyping:
+	default:
+		return fmt.Errorf("invalid typing command: %q", req.Command)
+	}
+
+	_, err := Request[struct{}](c).
+		Path(customTypingPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
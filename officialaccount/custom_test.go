@@ -0,0 +1,85 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestSendCustomMessage(t *testing.T) {
+	var got []map[string]any
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != customSendPath {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, body)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	ctx := context.Background()
+
+	messages := []CustomMessage{
+		CustomText{Content: "hello"},
+		CustomMsgMenu{HeadContent: "满意吗", List: []MsgMenuItem{{ID: "101", Content: "满意"}, {ID: "102", Content: "不满意"}}},
+		CustomMiniprogramPage{Title: "t", AppID: "wx1", PagePath: "pages/index", ThumbMediaID: "thumb"},
+	}
+	for _, msg := range messages {
+		if err := client.SendCustomMessage(ctx, SendCustomMessageRequest{ToUser: "o1", Message: msg, KfAccount: "test1@kftest"}); err != nil {
+			t.Fatalf("send %T: %v", msg, err)
+		}
+	}
+
+	if got[0]["msgtype"] != "text" || got[0]["text"].(map[string]any)["content"] != "hello" || got[0]["touser"] != "o1" {
+		t.Fatalf("unexpected text body: %v", got[0])
+	}
+	if got[0]["customservice"].(map[string]any)["kf_account"] != "test1@kftest" {
+		t.Fatalf("unexpected customservice: %v", got[0])
+	}
+	if _, ok := got[0]["image"]; ok {
+		t.Fatalf("unused message fields should be omitted: %v", got[0])
+	}
+	menu := got[1]["msgmenu"].(map[string]any)
+	if got[1]["msgtype"] != "msgmenu" || len(menu["list"].([]any)) != 2 || menu["head_content"] != "满意吗" {
+		t.Fatalf("unexpected msgmenu body: %v", got[1])
+	}
+	if got[2]["msgtype"] != "miniprogrampage" || got[2]["miniprogrampage"].(map[string]any)["pagepath"] != "pages/index" {
+		t.Fatalf("unexpected miniprogrampage body: %v", got[2])
+	}
+
+	invalid := []CustomMessage{
+		nil,
+		CustomText{},
+		CustomVideo{MediaID: "m"},
+		CustomNews{Articles: []CustomNewsArticle{{Title: "a"}, {Title: "b"}}},
+		CustomMsgMenu{List: []MsgMenuItem{{ID: "1"}}},
+	}
+	for _, msg := range invalid {
+		if err := client.SendCustomMessage(ctx, SendCustomMessageRequest{ToUser: "o1", Message: msg}); err == nil {
+			t.Fatalf("expected validation error for %#v", msg)
+		}
+	}
+	if len(got) != len(messages) {
+		t.Fatalf("invalid messages should not be sent, got %d requests", len(got))
+	}
+}
+
+func TestSetTyping(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req SetTypingRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != customTypingPath || req.Command != TypingCommandTyping || req.ToUser != "o1" {
+			t.Errorf("unexpected typing request: %s %+v", r.URL.Path, req)
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+
+	if err := client.SetTyping(context.Background(), SetTypingRequest{ToUser: "o1", Command: TypingCommandTyping}); err != nil {
+		t.Fatalf("set typing: %v", err)
+	}
+	if err := client.SetTyping(context.Background(), SetTypingRequest{ToUser: "o1", Command: "typing"}); err == nil {
+		t.Fatal("expected command validation error")
+	}
+}
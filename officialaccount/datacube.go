@@ -0,0 +1,429 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+const (
+	datacubeUserSummaryPath          = "/datacube/getusersummary"
+	datacubeUserCumulatePath         = "/datacube/getusercumulate"
+	datacubeArticleSummaryPath       = "/datacube/getarticlesummary"
+	datacubeArticleTotalPath         = "/datacube/getarticletotal"
+	datacubeUserReadPath             = "/datacube/getuserread"
+	datacubeUserReadHourPath         = "/datacube/getuserreadhour"
+	datacubeUserSharePath            = "/datacube/getusershare"
+	datacubeUserShareHourPath        = "/datacube/getusersharehour"
+	datacubeUpstreamMsgPath          = "/datacube/getupstreammsg"
+	datacubeUpstreamMsgHourPath      = "/datacube/getupstreammsghour"
+	datacubeUpstreamMsgWeekPath      = "/datacube/getupstreammsgweek"
+	datacubeUpstreamMsgMonthPath     = "/datacube/getupstreammsgmonth"
+	datacubeUpstreamMsgDistPath      = "/datacube/getupstreammsgdist"
+	datacubeUpstreamMsgDistWeekPath  = "/datacube/getupstreammsgdistweek"
+	datacubeUpstreamMsgDistMonthPath = "/datacube/getupstreammsgdistmonth"
+	datacubeInterfaceSummaryPath     = "/datacube/getinterfacesummary"
+	datacubeInterfaceSummaryHourPath = "/datacube/getinterfacesummaryhour"
+	publisherStatPath                = "/publisher/stat"
+	publisherActionAdPosGeneral      = "publisher_adpos_general"
+	publisherActionCPSGeneral        = "publisher_cps_general"
+	datacubeDateLayout               = "2006-01-02"
+	defaultPublisherStatPageSize     = 10
+	maxPublisherStatPageSize         = 100
+)
+
+// DateRange 统计日期范围，Begin 与 End 均包含在内，只取各自所在时区的日期部分。
+// 超过接口最大跨度的范围会自动拆分为多次请求，结果按日期顺序合并。
+type DateRange struct {
+	Begin time.Time
+	End   time.Time
+}
+
+// NewDateRange 创建 [begin, end] 的日期范围
+func NewDateRange(begin, end time.Time) DateRange {
+	return DateRange{Begin: begin, End: end}
+}
+
+// Days 范围内的天数
+func (r DateRange) Days() int {
+	begin, end := civilDate(r.Begin), civilDate(r.End)
+	return int(end.Sub(begin)/(24*time.Hour)) + 1
+}
+
+// dates 校验日期范围并格式化为 YYYY-MM-DD
+func (r DateRange) dates() (datacubeRequest, error) {
+	if r.Begin.IsZero() || r.End.IsZero() {
+		return datacubeRequest{}, fmt.Errorf("begin and end dates are required")
+	}
+	begin, end := civilDate(r.Begin), civilDate(r.End)
+	if end.Before(begin) {
+		return datacubeRequest{}, fmt.Errorf("end date %s is before begin date %s", end.Format(datacubeDateLayout), begin.Format(datacubeDateLayout))
+	}
+	return datacubeRequest{BeginDate: begin.Format(datacubeDateLayout), EndDate: end.Format(datacubeDateLayout)}, nil
+}
+
+// split 按最大跨度 maxDays 拆分日期范围
+func (r DateRange) split(maxDays int) ([]datacubeRequest, error) {
+	if _, err := r.dates(); err != nil {
+		return nil, err
+	}
+	begin, end := civilDate(r.Begin), civilDate(r.End)
+
+	var chunks []datacubeRequest
+	for start := begin; !start.After(end); start = start.AddDate(0, 0, maxDays) {
+		stop := start.AddDate(0, 0, maxDays-1)
+		if stop.After(end) {
+			stop = end
+		}
+		chunks = append(chunks, datacubeRequest{
+			BeginDate: start.Format(datacubeDateLayout),
+			EndDate:   stop.Format(datacubeDateLayout),
+		})
+	}
+	return chunks, nil
+}
+
+// civilDate 取 t 在其时区的日期，以 UTC 零点表示，避免夏令时影响按天计算
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+type datacubeRequest struct {
+	BeginDate string `json:"begin_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type datacubeResponse[T any] struct {
+	List []T `json:"list"`
+}
+
+// UserSummary 用户增减数据
+type UserSummary struct {
+	RefDate string `json:"ref_date"`
+	// UserSource 用户来源渠道，0 其他合计，1 公众号搜索，17 名片分享，30 扫描二维码，57 文章内账号名称，100 微信广告，161 他人转载，176 专辑页内账号名称
+	UserSource int `json:"user_source"`
+	NewUser    int `json:"new_user"`
+	CancelUser int `json:"cancel_user"`
+}
+
+// UserCumulate 累计用户数据
+type UserCumulate struct {
+	RefDate      string `json:"ref_date"`
+	CumulateUser int    `json:"cumulate_user"`
+}
+
+// ArticleSummary 图文群发每日数据
+type ArticleSummary struct {
+	RefDate          string `json:"ref_date"`
+	MsgID            string `json:"msgid"`
+	Title            string `json:"title"`
+	IntPageReadUser  int    `json:"int_page_read_user"`
+	IntPageReadCount int    `json:"int_page_read_count"`
+	OriPageReadUser  int    `json:"ori_page_read_user"`
+	OriPageReadCount int    `json:"ori_page_read_count"`
+	ShareUser        int    `json:"share_user"`
+	ShareCount       int    `json:"share_count"`
+	AddToFavUser     int    `json:"add_to_fav_user"`
+	AddToFavCount    int    `json:"add_to_fav_count"`
+}
+
+// ArticleTotal 图文群发总数据，Details 为群发后每天的累计数据
+type ArticleTotal struct {
+	RefDate string               `json:"ref_date"`
+	MsgID   string               `json:"msgid"`
+	Title   string               `json:"title"`
+	Details []ArticleTotalDetail `json:"details"`
+}
+
+type ArticleTotalDetail struct {
+	StatDate                    string `json:"stat_date"`
+	TargetUser                  int    `json:"target_user"`
+	IntPageReadUser             int    `json:"int_page_read_user"`
+	IntPageReadCount            int    `json:"int_page_read_count"`
+	OriPageReadUser             int    `json:"ori_page_read_user"`
+	OriPageReadCount            int    `json:"ori_page_read_count"`
+	ShareUser                   int    `json:"share_user"`
+	ShareCount                  int    `json:"share_count"`
+	AddToFavUser                int    `json:"add_to_fav_user"`
+	AddToFavCount               int    `json:"add_to_fav_count"`
+	IntPageFromSessionReadUser  int    `json:"int_page_from_session_read_user"`
+	IntPageFromSessionReadCount int    `json:"int_page_from_session_read_count"`
+	IntPageFromHistMsgReadUser  int    `json:"int_page_from_hist_msg_read_user"`
+	IntPageFromHistMsgReadCount int    `json:"int_page_from_hist_msg_read_count"`
+	IntPageFromFeedReadUser     int    `json:"int_page_from_feed_read_user"`
+	IntPageFromFeedReadCount    int    `json:"int_page_from_feed_read_count"`
+	IntPageFromFriendsReadUser  int    `json:"int_page_from_friends_read_user"`
+	IntPageFromFriendsReadCount int    `json:"int_page_from_friends_read_count"`
+	IntPageFromOtherReadUser    int    `json:"int_page_from_other_read_user"`
+	IntPageFromOtherReadCount   int    `json:"int_page_from_other_read_count"`
+	FeedShareFromSessionUser    int    `json:"feed_share_from_session_user"`
+	FeedShareFromSessionCnt     int    `json:"feed_share_from_session_cnt"`
+	FeedShareFromFeedUser       int    `json:"feed_share_from_feed_user"`
+	FeedShareFromFeedCnt        int    `json:"feed_share_from_feed_cnt"`
+	FeedShareFromOtherUser      int    `json:"feed_share_from_other_user"`
+	FeedShareFromOtherCnt       int    `json:"feed_share_from_other_cnt"`
+}
+
+// UserRead 图文统计数据，分时数据带 RefHour（如 1500 表示 15:00~16:00）
+type UserRead struct {
+	RefDate          string `json:"ref_date"`
+	RefHour          int    `json:"ref_hour,omitempty"`
+	UserSource       int    `json:"user_source"`
+	IntPageReadUser  int    `json:"int_page_read_user"`
+	IntPageReadCount int    `json:"int_page_read_count"`
+	OriPageReadUser  int    `json:"ori_page_read_user"`
+	OriPageReadCount int    `json:"ori_page_read_count"`
+	ShareUser        int    `json:"share_user"`
+	ShareCount       int    `json:"share_count"`
+	AddToFavUser     int    `json:"add_to_fav_user"`
+	AddToFavCount    int    `json:"add_to_fav_count"`
+}
+
+// UserShare 图文分享转发数据
+type UserShare struct {
+	RefDate string `json:"ref_date"`
+	RefHour int    `json:"ref_hour,omitempty"`
+	// ShareScene 分享场景，1 好友转发，2 朋友圈，255 其他
+	ShareScene int `json:"share_scene"`
+	ShareCount int `json:"share_count"`
+	ShareUser  int `json:"share_user"`
+}
+
+// UpstreamMsg 消息发送概况数据
+type UpstreamMsg struct {
+	RefDate string `json:"ref_date"`
+	RefHour int    `json:"ref_hour,omitempty"`
+	// MsgType 消息类型，1 文字，2 图片，3 语音，4 视频，6 第三方应用消息
+	MsgType  int `json:"msg_type"`
+	MsgUser  int `json:"msg_user"`
+	MsgCount int `json:"msg_count"`
+}
+
+// UpstreamMsgDist 消息发送分布数据
+type UpstreamMsgDist struct {
+	RefDate string `json:"ref_date"`
+	// CountInterval 发送次数区间，0 为 0 次，1 为 1~5 次，2 为 6~10 次，3 为 10 次以上
+	CountInterval int `json:"count_interval"`
+	MsgUser       int `json:"msg_user"`
+}
+
+// InterfaceSummary 接口分析数据
+type InterfaceSummary struct {
+	RefDate       string `json:"ref_date"`
+	RefHour       int    `json:"ref_hour,omitempty"`
+	CallbackCount int    `json:"callback_count"`
+	FailCount     int    `json:"fail_count"`
+	TotalTimeCost int64  `json:"total_time_cost"`
+	MaxTimeCost   int64  `json:"max_time_cost"`
+}
+
+// GetUserSummary 获取用户增减数据，最大跨度 7 天
+func (c *Client) GetUserSummary(ctx context.Context, r DateRange) ([]UserSummary, error) {
+	return fetchDatacube[UserSummary](ctx, c, datacubeUserSummaryPath, r, 7)
+}
+
+// GetUserCumulate 获取累计用户数据，最大跨度 7 天
+func (c *Client) GetUserCumulate(ctx context.Context, r DateRange) ([]UserCumulate, error) {
+	return fetchDatacube[UserCumulate](ctx, c, datacubeUserCumulatePath, r, 7)
+}
+
+// GetArticleSummary 获取图文群发每日数据，最大跨度 1 天
+func (c *Client) GetArticleSummary(ctx context.Context, r DateRange) ([]ArticleSummary, error) {
+	return fetchDatacube[ArticleSummary](ctx, c, datacubeArticleSummaryPath, r, 1)
+}
+
+// GetArticleTotal 获取图文群发总数据，最大跨度 1 天
+func (c *Client) GetArticleTotal(ctx context.Context, r DateRange) ([]ArticleTotal, error) {
+	return fetchDatacube[ArticleTotal](ctx, c, datacubeArticleTotalPath, r, 1)
+}
+
+// GetUserRead 获取图文统计数据，最大跨度 3 天
+func (c *Client) GetUserRead(ctx context.Context, r DateRange) ([]UserRead, error) {
+	return fetchDatacube[UserRead](ctx, c, datacubeUserReadPath, r, 3)
+}
+
+// GetUserReadHour 获取图文统计分时数据，最大跨度 1 天
+func (c *Client) GetUserReadHour(ctx context.Context, r DateRange) ([]UserRead, error) {
+	return fetchDatacube[UserRead](ctx, c, datacubeUserReadHourPath, r, 1)
+}
+
+// GetUserShare 获取图文分享转发数据，最大跨度 7 天
+func (c *Client) GetUserShare(ctx context.Context, r DateRange) ([]UserShare, error) {
+	return fetchDatacube[UserShare](ctx, c, datacubeUserSharePath, r, 7)
+}
+
+// GetUserShareHour 获取图文分享转发分时数据，最大跨度 1 天
+func (c *Client) GetUserShareHour(ctx context.Context, r DateRange) ([]UserShare, error) {
+	return fetchDatacube[UserShare](ctx, c, datacubeUserShareHourPath, r, 1)
+}
+
+// GetUpstreamMsg 获取消息发送概况数据，最大跨度 7 天
+func (c *Client) GetUpstreamMsg(ctx context.Context, r DateRange) ([]UpstreamMsg, error) {
+	return fetchDatacube[UpstreamMsg](ctx, c, datacubeUpstreamMsgPath, r, 7)
+}
+
+// GetUpstreamMsgHour 获取消息发送分时数据，最大跨度 1 天
+func (c *Client) GetUpstreamMsgHour(ctx context.Context, r DateRange) ([]UpstreamMsg, error) {
+	return fetchDatacube[UpstreamMsg](ctx, c, datacubeUpstreamMsgHourPath, r, 1)
+}
+
+// GetUpstreamMsgWeek 获取消息发送周数据，最大跨度 30 天
+func (c *Client) GetUpstreamMsgWeek(ctx context.Context, r DateRange) ([]UpstreamMsg, error) {
+	return fetchDatacube[UpstreamMsg](ctx, c, datacubeUpstreamMsgWeekPath, r, 30)
+}
+
+// GetUpstreamMsgMonth 获取消息发送月数据，最大跨度 30 天
+func (c *Client) GetUpstreamMsgMonth(ctx context.Context, r DateRange) ([]UpstreamMsg, error) {
+	return fetchDatacube[UpstreamMsg](ctx, c, datacubeUpstreamMsgMonthPath, r, 30)
+}
+
+// GetUpstreamMsgDist 获取消息发送分布数据，最大跨度 15 天
+func (c *Client) GetUpstreamMsgDist(ctx context.Context, r DateRange) ([]UpstreamMsgDist, error) {
+	return fetchDatacube[UpstreamMsgDist](ctx, c, datacubeUpstreamMsgDistPath, r, 15)
+}
+
+// GetUpstreamMsgDistWeek 获取消息发送分布周数据，最大跨度 30 天
+func (c *Client) GetUpstreamMsgDistWeek(ctx context.Context, r DateRange) ([]UpstreamMsgDist, error) {
+	return fetchDatacube[UpstreamMsgDist](ctx, c, datacubeUpstreamMsgDistWeekPath, r, 30)
+}
+
+// GetUpstreamMsgDistMonth 获取消息发送分布月数据，最大跨度 30 天
+func (c *Client) GetUpstreamMsgDistMonth(ctx context.Context, r DateRange) ([]UpstreamMsgDist, error) {
+	return fetchDatacube[UpstreamMsgDist](ctx, c, datacubeUpstreamMsgDistMonthPath, r, 30)
+}
+
+// GetInterfaceSummary 获取接口分析数据，最大跨度 30 天
+func (c *Client) GetInterfaceSummary(ctx context.Context, r DateRange) ([]InterfaceSummary, error) {
+	return fetchDatacube[InterfaceSummary](ctx, c, datacubeInterfaceSummaryPath, r, 30)
+}
+
+// GetInterfaceSummaryHour 获取接口分析分时数据，最大跨度 1 天
+func (c *Client) GetInterfaceSummaryHour(ctx context.Context, r DateRange) ([]InterfaceSummary, error) {
+	return fetchDatacube[InterfaceSummary](ctx, c, datacubeInterfaceSummaryHourPath, r, 1)
+}
+
+// fetchDatacube 按最大跨度拆分日期范围依次请求，结果按日期顺序合并
+func fetchDatacube[T any](ctx context.Context, c *Client, path string, r DateRange, maxDays int) ([]T, error) {
+	chunks, err := r.split(maxDays)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []T
+	for _, chunk := range chunks {
+		resp, err := Request[datacubeResponse[T]](c).
+			Path(path).
+			Body(chunk).
+			Idempotent(true).
+			Post(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("datacube %s to %s: %w", chunk.BeginDate, chunk.EndDate, err)
+		}
+		out = append(out, resp.List...)
+	}
+	return out, nil
+}
+
+type PublisherStatRequest struct {
+	Range DateRange
+	// Page 页码，从 1 开始，<= 0 时使用 1
+	Page int
+	// PageSize 每页条数，<= 0 时使用 10，最大 100
+	PageSize int
+	// AdSlot 广告位类型，如 SLOT_ID_BIZ_BOTTOM，为空时返回全部广告位；仅用于广告位数据
+	AdSlot string
+}
+
+// PublisherStatResponse 流量主数据的一页，Summary 为整个日期范围的汇总
+type PublisherStatResponse[T any] struct {
+	List     []T `json:"list"`
+	Summary  T   `json:"summary"`
+	TotalNum int `json:"total_num"`
+}
+
+// PublisherAdPosStat 广告位分日数据
+type PublisherAdPosStat struct {
+	SlotID        int64   `json:"slot_id"`
+	AdSlot        string  `json:"ad_slot"`
+	Date          string  `json:"date"`
+	ReqSuccCount  int64   `json:"req_succ_count"`
+	ExposureCount int64   `json:"exposure_count"`
+	ExposureRate  float64 `json:"exposure_rate"`
+	ClickCount    int64   `json:"click_count"`
+	ClickRate     float64 `json:"click_rate"`
+	// Income 收入，单位分
+	Income int64   `json:"income"`
+	ECPM   float64 `json:"ecpm"`
+}
+
+// PublisherCPSStat 返佣商品分日数据
+type PublisherCPSStat struct {
+	Date          string  `json:"date"`
+	ExposureCount int64   `json:"exposure_count"`
+	ClickCount    int64   `json:"click_count"`
+	ClickRate     float64 `json:"click_rate"`
+	OrderCount    int64   `json:"order_count"`
+	OrderRate     float64 `json:"order_rate"`
+	// TotalFee 订单总金额，单位分
+	TotalFee int64 `json:"total_fee"`
+	// TotalCommission 总佣金，单位分
+	TotalCommission int64 `json:"total_commission"`
+}
+
+type publisherStatAPIResponse[T any] struct {
+	BaseResp struct {
+		ErrMsg string `json:"err_msg"`
+		Ret    int    `json:"ret"`
+	} `json:"base_resp"`
+	PublisherStatResponse[T]
+}
+
+// GetPublisherAdPosGeneral 获取公众号流量主广告位分日数据
+func (c *Client) GetPublisherAdPosGeneral(ctx context.Context, req PublisherStatRequest) (PublisherStatResponse[PublisherAdPosStat], error) {
+	return getPublisherStat[PublisherAdPosStat](ctx, c, publisherActionAdPosGeneral, req)
+}
+
+// GetPublisherCPSGeneral 获取公众号流量主返佣商品分日数据
+func (c *Client) GetPublisherCPSGeneral(ctx context.Context, req PublisherStatRequest) (PublisherStatResponse[PublisherCPSStat], error) {
+	req.AdSlot = ""
+	return getPublisherStat[PublisherCPSStat](ctx, c, publisherActionCPSGeneral, req)
+}
+
+// getPublisherStat 流量主接口以 base_resp.ret 而非 errcode 返回错误，统一转换为 *core.WechatError
+func getPublisherStat[T any](ctx context.Context, c *Client, action string, req PublisherStatRequest) (PublisherStatResponse[T], error) {
+	dates, err := req.Range.dates()
+	if err != nil {
+		return PublisherStatResponse[T]{}, err
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPublisherStatPageSize
+	}
+	if pageSize > maxPublisherStatPageSize {
+		return PublisherStatResponse[T]{}, fmt.Errorf("page_size exceeds %d", maxPublisherStatPageSize)
+	}
+
+	r := Request[publisherStatAPIResponse[T]](c).
+		Path(publisherStatPath).
+		Query("action", action).
+		Query("page", strconv.Itoa(max(req.Page, 1))).
+		Query("page_size", strconv.Itoa(pageSize)).
+		Query("start_date", dates.BeginDate).
+		Query("end_date", dates.EndDate).
+		Idempotent(true)
+	if req.AdSlot != "" {
+		r = r.Query("ad_slot", req.AdSlot)
+	}
+	resp, err := r.Get(ctx)
+	if err != nil {
+		return PublisherStatResponse[T]{}, err
+	}
+	if resp.BaseResp.Ret != 0 {
+		return PublisherStatResponse[T]{}, &core.WechatError{ErrCode: resp.BaseResp.Ret, ErrMsg: resp.BaseResp.ErrMsg}
+	}
+	return resp.PublisherStatResponse, nil
+}
@@ -0,0 +1,125 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+func mustDate(s string) time.Time {
+	t, err := time.Parse(datacubeDateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestDateRangeSplit(t *testing.T) {
+	chunks, err := NewDateRange(mustDate("2026-01-30"), mustDate("2026-02-15")).split(7)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	want := []datacubeRequest{
+		{BeginDate: "2026-01-30", EndDate: "2026-02-05"},
+		{BeginDate: "2026-02-06", EndDate: "2026-02-12"},
+		{BeginDate: "2026-02-13", EndDate: "2026-02-15"},
+	}
+	if fmt.Sprint(chunks) != fmt.Sprint(want) {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+
+	// 不同时区的时间按各自的日期计算
+	shanghai := time.FixedZone("CST", 8*3600)
+	r := NewDateRange(time.Date(2026, 3, 1, 23, 30, 0, 0, shanghai), time.Date(2026, 3, 1, 0, 10, 0, 0, shanghai))
+	if chunks, err := r.split(1); err != nil || len(chunks) != 1 || chunks[0].BeginDate != "2026-03-01" || r.Days() != 1 {
+		t.Fatalf("unexpected same-day split: %v %v", chunks, err)
+	}
+
+	if _, err := NewDateRange(mustDate("2026-02-02"), mustDate("2026-02-01")).split(1); err == nil {
+		t.Fatal("expected reversed range error")
+	}
+	if _, err := (DateRange{End: mustDate("2026-02-01")}).split(1); err == nil {
+		t.Fatal("expected missing begin error")
+	}
+}
+
+func TestGetUserSummarySplitsRange(t *testing.T) {
+	var ranges []datacubeRequest
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != datacubeUserSummaryPath {
+			http.NotFound(w, r)
+			return
+		}
+		var req datacubeRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		ranges = append(ranges, req)
+		_, _ = fmt.Fprintf(w, `{"list":[{"ref_date":"%s","user_source":0,"new_user":%d,"cancel_user":1}]}`, req.BeginDate, len(ranges))
+	})
+
+	list, err := client.GetUserSummary(context.Background(), NewDateRange(mustDate("2026-01-01"), mustDate("2026-01-10")))
+	if err != nil {
+		t.Fatalf("get user summary: %v", err)
+	}
+	if len(ranges) != 2 || ranges[0].EndDate != "2026-01-07" || ranges[1].BeginDate != "2026-01-08" {
+		t.Fatalf("unexpected requests: %v", ranges)
+	}
+	if len(list) != 2 || list[0].RefDate != "2026-01-01" || list[1].NewUser != 2 {
+		t.Fatalf("results should be merged in order: %+v", list)
+	}
+}
+
+func TestDatacubeChunkError(t *testing.T) {
+	calls := 0
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			_, _ = w.Write([]byte(`{"errcode":61501,"errmsg":"date range error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"list":[{"ref_date":"2026-01-01","ref_hour":1500,"user_source":0,"int_page_read_user":10}]}`))
+	})
+
+	_, err := client.GetUserReadHour(context.Background(), NewDateRange(mustDate("2026-01-01"), mustDate("2026-01-03")))
+	var we *core.WechatError
+	if !errors.As(err, &we) || we.ErrCode != 61501 || calls != 2 {
+		t.Fatalf("expected error from second chunk, got %v after %d calls", err, calls)
+	}
+}
+
+func TestPublisherStat(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != publisherStatPath || r.Method != http.MethodGet {
+			http.NotFound(w, r)
+			return
+		}
+		switch q.Get("action") {
+		case publisherActionAdPosGeneral:
+			if q.Get("page") != "1" || q.Get("page_size") != "10" || q.Get("start_date") != "2026-01-01" || q.Get("end_date") != "2026-01-31" || q.Get("ad_slot") != "SLOT_ID_BIZ_BOTTOM" {
+				t.Errorf("unexpected query: %v", q)
+			}
+			_, _ = w.Write([]byte(`{"base_resp":{"err_msg":"ok","ret":0},"list":[{"slot_id":1,"ad_slot":"SLOT_ID_BIZ_BOTTOM","date":"2026-01-01","exposure_count":100,"income":35}],"summary":{"exposure_count":100,"income":35},"total_num":1}`))
+		default:
+			_, _ = w.Write([]byte(`{"base_resp":{"err_msg":"no permission","ret":45009}}`))
+		}
+	})
+	ctx := context.Background()
+	r := NewDateRange(mustDate("2026-01-01"), mustDate("2026-01-31"))
+
+	adpos, err := client.GetPublisherAdPosGeneral(ctx, PublisherStatRequest{Range: r, AdSlot: "SLOT_ID_BIZ_BOTTOM"})
+	if err != nil || adpos.TotalNum != 1 || adpos.List[0].Income != 35 || adpos.Summary.ExposureCount != 100 {
+		t.Fatalf("adpos general: %+v %v", adpos, err)
+	}
+
+	_, err = client.GetPublisherCPSGeneral(ctx, PublisherStatRequest{Range: r})
+	var we *core.WechatError
+	if !errors.As(err, &we) || we.ErrCode != 45009 {
+		t.Fatalf("expected base_resp error, got %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package officialaccount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	draftAddPath             = "/cgi-bin/draft/add"
+	draftGetPath             = "/cgi-bin/draft/get"
+	draftDeletePath          = "/cgi-bin/draft/delete"
+	draftUpdatePath          = "/cgi-bin/draft/update"
+	draftCountPath           = "/cgi-bin/draft/count"
+	draftBatchGetPath        = "/cgi-bin/draft/batchget"
+	freePublishSubmitPath    = "/cgi-bin/freepublish/submit"
+	freePublishGetPath       = "/cgi-bin/freepublish/get"
+	freePublishDeletePath    = "/cgi-bin/freepublish/delete"
+	freePublishArticlePath   = "/cgi-bin/freepublish/getarticle"
+	freePublishBatchGetPath  = "/cgi-bin/freepublish/batchget"
+	maxDraftBatchGetCount    = 20
+	defaultPublishPollPeriod = 3 * time.Second
+)
+
+// ArticleType 图文类型
+type ArticleType string
+
+const (
+	// ArticleTypeNews 图文消息
+	ArticleTypeNews ArticleType = "news"
+	// ArticleTypeNewsPic 图片消息，图片通过 ImageInfo 设置
+	ArticleTypeNewsPic ArticleType = "newspic"
+)
+
+// PublishStatus 发布状态
+type PublishStatus int
+
+const (
+	PublishStatusSuccess        PublishStatus = 0
+	PublishStatusPublishing     PublishStatus = 1
+	PublishStatusOriginalFailed PublishStatus = 2
+	PublishStatusFailed         PublishStatus = 3
+	PublishStatusAuditFailed    PublishStatus = 4
+	PublishStatusDeleted        PublishStatus = 5
+	PublishStatusBanned         PublishStatus = 6
+)
+
+// ErrPublishFailed 发布未成功（原创校验失败、审核不通过、发布后被删除或封禁等）
+var ErrPublishFailed = errors.New("publish failed")
+
+// Article 草稿与已发布内容中的单篇文章
+type Article struct {
+	ArticleType ArticleType `json:"article_type,omitempty"`
+	Title       string      `json:"title"`
+	Author      string      `json:"author,omitempty"`
+	// Digest 摘要，仅单图文有效，不填默认抓取正文前 54 个字
+	Digest  string `json:"digest,omitempty"`
+	Content string `json:"content"`
+	// ContentSourceURL 阅读原文地址
+	ContentSourceURL string `json:"content_source_url,omitempty"`
+	// ThumbMediaID 封面图片的永久素材 ID，图文消息必填
+	ThumbMediaID       string `json:"thumb_media_id,omitempty"`
+	NeedOpenComment    int    `json:"need_open_comment,omitempty"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment,omitempty"`
+	// PicCrop2351 封面裁剪为 2.35:1 的坐标，格式为 X1_Y1_X2_Y2（0~1 的比例），如 0.1945_0_1_0.5236
+	PicCrop2351 string `json:"pic_crop_235_1,omitempty"`
+	// PicCrop11 封面裁剪为 1:1 的坐标，格式同 PicCrop2351
+	PicCrop11 string `json:"pic_crop_1_1,omitempty"`
+	// ImageInfo 图片消息的图片列表，ArticleType 为 newspic 时必填
+	ImageInfo *ArticleImageInfo `json:"image_info,omitempty"`
+
+	// 以下字段仅在查询时返回
+	URL        string `json:"url,omitempty"`
+	ThumbURL   string `json:"thumb_url,omitempty"`
+	IsDeleted  bool   `json:"is_deleted,omitempty"`
+	ShowCover  int    `json:"show_cover_pic,omitempty"`
+	UpdateTime int64  `json:"update_time,omitempty"`
+}
+
+// ArticleImageInfo 图片消息的图片列表，最多 20 张
+type ArticleImageInfo struct {
+	ImageList []ArticleImage `json:"image_list"`
+}
+
+type ArticleImage struct {
+	ImageMediaID string `json:"image_media_id"`
+}
+
+type AddDraftRequest struct {
+	Articles []Article `json:"articles"`
+}
+
+type AddDraftResponse struct {
+	MediaID string `json:"media_id"`
+}
+
+type GetDraftRequest struct {
+	MediaID string `json:"media_id"`
+}
+
+type NewsContent struct {
+	NewsItem []Article `json:"news_item"`
+}
+
+type DeleteDraftRequest struct {
+	MediaID string `json:"media_id"`
+}
+
+type UpdateDraftRequest struct {
+	MediaID string `json:"media_id"`
+	// Index 要更新的文章在图文中的位置，从 0 开始
+	Index    int     `json:"index"`
+	Articles Article `json:"articles"`
+}
+
+type GetDraftCountResponse struct {
+	TotalCount int `json:"total_count"`
+}
+
+type BatchGetDraftRequest struct {
+	Offset int `json:"offset"`
+	// Count 返回数量，取值 1~20，<= 0 时使用 20
+	Count int `json:"count"`
+	// NoContent 为 1 时不返回 content 字段
+	NoContent int `json:"no_content,omitempty"`
+}
+
+type DraftItem struct {
+	MediaID    string      `json:"media_id"`
+	Content    NewsContent `json:"content"`
+	UpdateTime int64       `json:"update_time"`
+}
+
+type BatchGetDraftResponse struct {
+	TotalCount int         `json:"total_count"`
+	ItemCount  int         `json:"item_count"`
+	Item       []DraftItem `json:"item"`
+}
+
+type SubmitPublishRequest struct {
+	MediaID string `json:"media_id"`
+}
+
+type SubmitPublishResponse struct {
+	PublishID string `json:"publish_id"`
+	MsgDataID int64  `json:"msg_data_id"`
+}
+
+type GetPublishStatusRequest struct {
+	PublishID string `json:"publish_id"`
+}
+
+type PublishArticleDetail struct {
+	Count int `json:"count"`
+	Item  []struct {
+		Idx        int    `json:"idx"`
+		ArticleURL string `json:"article_url"`
+	} `json:"item"`
+}
+
+type GetPublishStatusResponse struct {
+	PublishID     string               `json:"publish_id"`
+	PublishStatus PublishStatus        `json:"publish_status"`
+	ArticleID     string               `json:"article_id"`
+	ArticleDetail PublishArticleDetail `json:"article_detail"`
+	// FailIdx 原创校验或审核失败的文章编号，从 1 开始
+	FailIdx []int `json:"fail_idx"`
+}
+
+type WaitPublishRequest struct {
+	PublishID string
+	// Interval 轮询间隔，<= 0 时使用 3 秒
+	Interval time.Duration
+}
+
+type DeletePublishRequest struct {
+	ArticleID string `json:"article_id"`
+	// Index 要删除的文章编号，从 1 开始；为 0 时删除全部文章
+	Index int `json:"index,omitempty"`
+}
+
+type GetPublishArticleRequest struct {
+	ArticleID string `json:"article_id"`
+}
+
+type BatchGetPublishRequest = BatchGetDraftRequest
+
+type PublishItem struct {
+	ArticleID  string      `json:"article_id"`
+	Content    NewsContent `json:"content"`
+	UpdateTime int64       `json:"update_time"`
+}
+
+type BatchGetPublishResponse struct {
+	TotalCount int           `json:"total_count"`
+	ItemCount  int           `json:"item_count"`
+	Item       []PublishItem `json:"item"`
+}
+
+// AddDraft 新建草稿，返回草稿的 media_id
+func (c *Client) AddDraft(ctx context.Context, req AddDraftRequest) (AddDraftResponse, error) {
+	if len(req.Articles) == 0 {
+		return AddDraftResponse{}, fmt.Errorf("articles are required")
+	}
+	for i, article := range req.Articles {
+		if err := validateArticle(article); err != nil {
+			return AddDraftResponse{}, fmt.Errorf("articles[%d]: %w", i, err)
+		}
+	}
+
+	return Request[AddDraftResponse](c).
+		Path(draftAddPath).
+		Body(req).
+		Post(ctx)
+}
+
+// GetDraft 获取草稿内容
+func (c *Client) GetDraft(ctx context.Context, req GetDraftRequest) (NewsContent, error) {
+	if req.MediaID == "" {
+		return NewsContent{}, fmt.Errorf("media_id is required")
+	}
+
+	return Request[NewsContent](c).
+		Path(draftGetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// DeleteDraft 删除草稿，删除后无法恢复
+func (c *Client) DeleteDraft(ctx context.Context, req DeleteDraftRequest) error {
+	if req.MediaID == "" {
+		return fmt.Errorf("media_id is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(draftDeletePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// UpdateDraft 修改草稿中的单篇文章
+func (c *Client) UpdateDraft(ctx context.Context, req UpdateDraftRequest) error {
+	if req.MediaID == "" {
+		return fmt.Errorf("media_id is required")
+	}
+	if req.Index < 0 {
+		return fmt.Errorf("index must not be negative")
+	}
+	if err := validateArticle(req.Articles); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(draftUpdatePath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// GetDraftCount 获取草稿总数
+func (c *Client) GetDraftCount(ctx context.Context) (GetDraftCountResponse, error) {
+	return Request[GetDraftCountResponse](c).
+		Path(draftCountPath).
+		Get(ctx)
+}
+
+// BatchGetDraft 分页获取草稿列表
+func (c *Client) BatchGetDraft(ctx context.Context, req BatchGetDraftRequest) (BatchGetDraftResponse, error) {
+	return Request[BatchGetDraftResponse](c).
+		Path(draftBatchGetPath).
+		Body(normalizeBatchGetDraft(req)).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// SubmitPublish 发布草稿，发布结果通过 GetPublishStatus、WaitPublish 或 PUBLISHJOBFINISH 事件获取
+func (c *Client) SubmitPublish(ctx context.Context, req SubmitPublishRequest) (SubmitPublishResponse, error) {
+	if req.MediaID == "" {
+		return SubmitPublishResponse{}, fmt.Errorf("media_id is required")
+	}
+
+	return Request[SubmitPublishResponse](c).
+		Path(freePublishSubmitPath).
+		Body(req).
+		Post(ctx)
+}
+
+// GetPublishStatus 查询发布状态
+func (c *Client) GetPublishStatus(ctx context.Context, req GetPublishStatusRequest) (GetPublishStatusResponse, error) {
+	if req.PublishID == "" {
+		return GetPublishStatusResponse{}, fmt.Errorf("publish_id is required")
+	}
+
+	return Request[GetPublishStatusResponse](c).
+		Path(freePublishGetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// WaitPublish 轮询发布状态直到发布结束或 ctx 取消。
+// 发布成功时返回结果与 nil；发布失败时同时返回结果与包装 ErrPublishFailed 的错误，可从结果中读取 FailIdx。
+func (c *Client) WaitPublish(ctx context.Context, req WaitPublishRequest) (GetPublishStatusResponse, error) {
+	if req.PublishID == "" {
+		return GetPublishStatusResponse{}, fmt.Errorf("publish_id is required")
+	}
+	interval := req.Interval
+	if interval <= 0 {
+		interval = defaultPublishPollPeriod
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return GetPublishStatusResponse{}, ctx.Err()
+		case <-timer.C:
+		}
+
+		resp, err := c.GetPublishStatus(ctx, GetPublishStatusRequest{PublishID: req.PublishID})
+		if err != nil {
+			return GetPublishStatusResponse{}, err
+		}
+		switch resp.PublishStatus {
+		case PublishStatusPublishing:
+			timer.Reset(interval)
+		case PublishStatusSuccess:
+			return resp, nil
+		default:
+			return resp, fmt.Errorf("%w: publish_status %d, fail_idx %v", ErrPublishFailed, resp.PublishStatus, resp.FailIdx)
+		}
+	}
+}
+
+// DeletePublish 删除已发布的文章，删除后无法恢复
+func (c *Client) DeletePublish(ctx context.Context, req DeletePublishRequest) error {
+	if req.ArticleID == "" {
+		return fmt.Errorf("article_id is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(freePublishDeletePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// GetPublishArticle 获取已发布的图文内容
+func (c *Client) GetPublishArticle(ctx context.Context, req GetPublishArticleRequest) (NewsContent, error) {
+	if req.ArticleID == "" {
+		return NewsContent{}, fmt.Errorf("article_id is required")
+	}
+
+	return Request[NewsContent](c).
+		Path(freePublishArticlePath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// BatchGetPublish 分页获取已成功发布的图文列表
+func (c *Client) BatchGetPublish(ctx context.Context, req BatchGetPublishRequest) (BatchGetPublishResponse, error) {
+	return Request[BatchGetPublishResponse](c).
+		Path(freePublishBatchGetPath).
+		Body(normalizeBatchGetDraft(req)).
+		Idempotent(true).
+		Post(ctx)
+}
+
+func normalizeBatchGetDraft(req BatchGetDraftRequest) BatchGetDraftRequest {
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+	if req.Count <= 0 || req.Count > maxDraftBatchGetCount {
+		req.Count = maxDraftBatchGetCount
+	}
+	return req
+}
+
+func validateArticle(article Article) error {
+	if article.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if article.Content == "" {
+		return fmt.Errorf("content is required")
+	}
+	switch article.ArticleType {
+	case "", ArticleTypeNews:
+		if article.ThumbMediaID == "" {
+			return fmt.Errorf("thumb_media_id is required")
+		}
+	case ArticleTypeNewsPic:
+		if article.ImageInfo == nil || len(article.ImageInfo.ImageList) == 0 {
+			return fmt.Errorf("image_info is required for newspic")
+		}
+	default:
+		return fmt.Errorf("invalid article_type: %q", article.ArticleType)
+	}
+	return nil
+}
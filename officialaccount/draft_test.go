@@ -0,0 +1,211 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDraft(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+		}
+		switch r.URL.Path {
+		case draftAddPath:
+			article := body["articles"].([]any)[0].(map[string]any)
+			if article["thumb_media_id"] != "thumb-1" || article["pic_crop_235_1"] != "0.1945_0_1_0.5236" || article["need_open_comment"] != float64(1) {
+				t.Errorf("unexpected article: %v", article)
+			}
+			if _, ok := article["url"]; ok {
+				t.Errorf("query-only fields should be omitted: %v", article)
+			}
+			_, _ = w.Write([]byte(`{"media_id":"draft-1"}`))
+		case draftGetPath:
+			_, _ = w.Write([]byte(`{"news_item":[{"title":"t","content":"c","thumb_media_id":"thumb-1","url":"https://mp.weixin.qq.com/s/1"}]}`))
+		case draftCountPath:
+			_, _ = w.Write([]byte(`{"total_count":3}`))
+		case draftBatchGetPath:
+			if body["count"] != float64(maxDraftBatchGetCount) || body["no_content"] != float64(1) {
+				t.Errorf("unexpected batchget body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"total_count":3,"item_count":1,"item":[{"media_id":"draft-1","content":{"news_item":[]},"update_time":1700000000}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	added, err := client.AddDraft(ctx, AddDraftRequest{Articles: []Article{{
+		Title:           "t",
+		Content:         "c",
+		ThumbMediaID:    "thumb-1",
+		NeedOpenComment: 1,
+		PicCrop2351:     "0.1945_0_1_0.5236",
+	}}})
+	if err != nil || added.MediaID != "draft-1" {
+		t.Fatalf("add draft: %+v %v", added, err)
+	}
+	draft, err := client.GetDraft(ctx, GetDraftRequest{MediaID: added.MediaID})
+	if err != nil || len(draft.NewsItem) != 1 || draft.NewsItem[0].URL != "https://mp.weixin.qq.com/s/1" {
+		t.Fatalf("get draft: %+v %v", draft, err)
+	}
+	count, err := client.GetDraftCount(ctx)
+	if err != nil || count.TotalCount != 3 {
+		t.Fatalf("draft count: %+v %v", count, err)
+	}
+	list, err := client.BatchGetDraft(ctx, BatchGetDraftRequest{Count: 50, NoContent: 1})
+	if err != nil || list.Item[0].MediaID != "draft-1" {
+		t.Fatalf("batchget draft: %+v %v", list, err)
+	}
+
+	invalid := []Article{
+		{Title: "t", Content: "c"},
+		{Title: "t", Content: "c", ArticleType: ArticleTypeNewsPic},
+		{Title: "t", Content: "c", ArticleType: "video", ThumbMediaID: "thumb-1"},
+	}
+	for _, article := range invalid {
+		if _, err := client.AddDraft(ctx, AddDraftRequest{Articles: []Article{article}}); err == nil {
+			t.Fatalf("expected validation error for %+v", article)
+		}
+	}
+}
+
+func TestWaitPublish(t *testing.T) {
+	polls := 0
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req GetPublishStatusRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != freePublishGetPath {
+			http.NotFound(w, r)
+			return
+		}
+		polls++
+		switch {
+		case req.PublishID == "fail":
+			_, _ = w.Write([]byte(`{"publish_id":"fail","publish_status":2,"fail_idx":[1,2]}`))
+		case polls < 3:
+			_, _ = w.Write([]byte(`{"publish_id":"p1","publish_status":1}`))
+		default:
+			_, _ = w.Write([]byte(`{"publish_id":"p1","publish_status":0,"article_id":"a1","article_detail":{"count":1,"item":[{"idx":1,"article_url":"https://mp.weixin.qq.com/s/1"}]}}`))
+		}
+	})
+	ctx := context.Background()
+
+	resp, err := client.WaitPublish(ctx, WaitPublishRequest{PublishID: "p1", Interval: time.Millisecond})
+	if err != nil || resp.ArticleID != "a1" || resp.ArticleDetail.Item[0].ArticleURL == "" || polls != 3 {
+		t.Fatalf("wait publish: %+v %v after %d polls", resp, err, polls)
+	}
+
+	resp, err = client.WaitPublish(ctx, WaitPublishRequest{PublishID: "fail", Interval: time.Millisecond})
+	if !errors.Is(err, ErrPublishFailed) || resp.PublishStatus != PublishStatusOriginalFailed || len(resp.FailIdx) != 2 {
+		t.Fatalf("expected ErrPublishFailed, got %+v %v", resp, err)
+	}
+
+	polls = 0
+	cancelCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.WaitPublish(cancelCtx, WaitPublishRequest{PublishID: "p1", Interval: time.Hour}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestDraftUpdateAndDelete(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case draftUpdatePath:
+			article := body["articles"].(map[string]any)
+			if body["media_id"] != "draft-1" || body["index"] != float64(1) || article["title"] != "new title" || article["pic_crop_1_1"] != "0_0_1_1" {
+				t.Errorf("unexpected update body: %v", body)
+			}
+		case draftDeletePath:
+			if body["media_id"] != "draft-1" {
+				t.Errorf("unexpected delete body: %v", body)
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	})
+	ctx := context.Background()
+
+	err := client.UpdateDraft(ctx, UpdateDraftRequest{
+		MediaID:  "draft-1",
+		Index:    1,
+		Articles: Article{Title: "new title", Content: "c", ThumbMediaID: "thumb-1", PicCrop11: "0_0_1_1"},
+	})
+	if err != nil {
+		t.Fatalf("update draft: %v", err)
+	}
+	if err := client.UpdateDraft(ctx, UpdateDraftRequest{MediaID: "draft-1", Index: -1}); err == nil {
+		t.Fatal("expected index validation error")
+	}
+	if err := client.DeleteDraft(ctx, DeleteDraftRequest{MediaID: "draft-1"}); err != nil {
+		t.Fatalf("delete draft: %v", err)
+	}
+	if err := client.DeleteDraft(ctx, DeleteDraftRequest{}); err == nil {
+		t.Fatal("expected media_id validation error")
+	}
+}
+
+func TestFreePublish(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case freePublishSubmitPath:
+			if body["media_id"] != "draft-1" {
+				t.Errorf("unexpected submit body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","publish_id":"100000001","msg_data_id":2247483650}`))
+		case freePublishDeletePath:
+			if body["article_id"] != "a1" || body["index"] != float64(2) {
+				t.Errorf("unexpected delete body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case freePublishArticlePath:
+			if body["article_id"] != "a1" {
+				t.Errorf("unexpected getarticle body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"news_item":[{"title":"t","content":"c","thumb_media_id":"thumb-1","url":"https://mp.weixin.qq.com/s/1","is_deleted":false}]}`))
+		case freePublishBatchGetPath:
+			if body["offset"] != float64(20) || body["count"] != float64(5) {
+				t.Errorf("unexpected batchget body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"total_count":21,"item_count":1,"item":[{"article_id":"a1","content":{"news_item":[{"title":"t","url":"https://mp.weixin.qq.com/s/1"}]},"update_time":1700000000}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	submitted, err := client.SubmitPublish(ctx, SubmitPublishRequest{MediaID: "draft-1"})
+	if err != nil || submitted.PublishID != "100000001" || submitted.MsgDataID != 2247483650 {
+		t.Fatalf("submit publish: %+v %v", submitted, err)
+	}
+	if err := client.DeletePublish(ctx, DeletePublishRequest{ArticleID: "a1", Index: 2}); err != nil {
+		t.Fatalf("delete publish: %v", err)
+	}
+	article, err := client.GetPublishArticle(ctx, GetPublishArticleRequest{ArticleID: "a1"})
+	if err != nil || len(article.NewsItem) != 1 || article.NewsItem[0].URL != "https://mp.weixin.qq.com/s/1" {
+		t.Fatalf("get publish article: %+v %v", article, err)
+	}
+	list, err := client.BatchGetPublish(ctx, BatchGetPublishRequest{Offset: 20, Count: 5})
+	if err != nil || list.TotalCount != 21 || list.Item[0].ArticleID != "a1" || list.Item[0].Content.NewsItem[0].Title != "t" {
+		t.Fatalf("batchget publish: %+v %v", list, err)
+	}
+
+	if err := client.DeletePublish(ctx, DeletePublishRequest{}); err == nil {
+		t.Fatal("expected article_id validation error")
+	}
+	if _, err := client.GetPublishArticle(ctx, GetPublishArticleRequest{}); err == nil {
+		t.Fatal("expected article_id validation error")
+	}
+}
@@ -0,0 +1,347 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+)
+
+const (
+	kfAccountAddPath           = "/customservice/kfaccount/add"
+	kfAccountUpdatePath        = "/customservice/kfaccount/update"
+	kfAccountDeletePath        = "/customservice/kfaccount/del"
+	kfAccountUploadHeadImgPath = "/customservice/kfaccount/uploadheadimg"
+	kfListPath                 = "/cgi-bin/customservice/getkflist"
+	kfOnlineListPath           = "/cgi-bin/customservice/getonlinekflist"
+	kfSessionCreatePath        = "/customservice/kfsession/create"
+	kfSessionClosePath         = "/customservice/kfsession/close"
+	kfSessionGetPath           = "/customservice/kfsession/getsession"
+	kfSessionListPath          = "/customservice/kfsession/getsessionlist"
+	kfSessionWaitCasePath      = "/customservice/kfsession/getwaitcase"
+	kfMsgRecordListPath        = "/customservice/msgrecord/getmsglist"
+	maxMsgRecordNumber         = 10000
+	maxMsgRecordSpanSeconds    = 24 * 60 * 60
+)
+
+// 客服头像仅支持 jpg
+var kfHeadImageLimit = mediaLimit{exts: []string{".jpg", ".jpeg"}, maxSize: 10 << 20}
+
+// KfAccount 客服账号信息
+type KfAccount struct {
+	KfAccount    string `json:"kf_account"`
+	KfNick       string `json:"kf_nick"`
+	KfID         string `json:"kf_id"`
+	KfHeadImgURL string `json:"kf_headimgurl"`
+	// KfWx 已绑定的微信号，未绑定时为空
+	KfWx string `json:"kf_wx"`
+	// InviteWx 等待确认邀请的微信号
+	InviteWx         string `json:"invite_wx"`
+	InviteExpireTime int64  `json:"invite_expire_time"`
+	InviteStatus     string `json:"invite_status"`
+}
+
+// OnlineKfAccount 在线客服状态
+type OnlineKfAccount struct {
+	KfAccount string `json:"kf_account"`
+	// Status 1 为 web 在线
+	Status       int    `json:"status"`
+	KfID         string `json:"kf_id"`
+	AcceptedCase int    `json:"accepted_case"`
+}
+
+type KfAccountRequest struct {
+	// KfAccount 完整客服账号，格式为 账号前缀@公众号微信号
+	KfAccount string `json:"kf_account"`
+	Nickname  string `json:"nickname"`
+}
+
+type DeleteKfAccountRequest struct {
+	KfAccount string
+}
+
+type UploadKfHeadImageRequest struct {
+	KfAccount string
+	FileName  string
+	File      io.Reader
+}
+
+type GetKfListResponse struct {
+	KfList []KfAccount `json:"kf_list"`
+}
+
+type GetOnlineKfListResponse struct {
+	KfOnlineList []OnlineKfAccount `json:"kf_online_list"`
+}
+
+type KfSessionRequest struct {
+	KfAccount string `json:"kf_account"`
+	OpenID    string `json:"openid"`
+}
+
+type GetKfSessionRequest struct {
+	OpenID string
+}
+
+type GetKfSessionResponse struct {
+	KfAccount  string `json:"kf_account"`
+	CreateTime int64  `json:"createtime"`
+}
+
+type GetKfSessionListRequest struct {
+	KfAccount string
+}
+
+type KfSession struct {
+	OpenID     string `json:"openid"`
+	CreateTime int64  `json:"createtime"`
+}
+
+type GetKfSessionListResponse struct {
+	SessionList []KfSession `json:"sessionlist"`
+}
+
+type KfWaitCase struct {
+	OpenID     string `json:"openid"`
+	LatestTime int64  `json:"latest_time"`
+}
+
+type GetKfWaitCaseResponse struct {
+	Count        int          `json:"count"`
+	WaitCaseList []KfWaitCase `json:"waitcaselist"`
+}
+
+type GetMsgRecordListRequest struct {
+	// StartTime、EndTime 为 Unix 秒，时间跨度不能超过 24 小时
+	StartTime int64 `json:"starttime"`
+	EndTime   int64 `json:"endtime"`
+	// MsgID 消息 ID 游标，首次传 1
+	MsgID int64 `json:"msgid"`
+	// Number 每次获取条数，最多 10000，<= 0 时使用 10000
+	Number int `json:"number"`
+}
+
+// MsgRecord 客服聊天记录
+type MsgRecord struct {
+	OpenID string `json:"openid"`
+	// OperCode 操作码，2002 客服发送消息，2003 客服接收消息
+	OperCode int    `json:"opercode"`
+	Text     string `json:"text"`
+	Time     int64  `json:"time"`
+	Worker   string `json:"worker"`
+}
+
+type GetMsgRecordListResponse struct {
+	RecordList []MsgRecord `json:"recordlist"`
+	Number     int         `json:"number"`
+	// MsgID 下一页的游标
+	MsgID int64 `json:"msgid"`
+}
+
+// AddKfAccount 添加客服账号，添加后需邀请微信号绑定
+func (c *Client) AddKfAccount(ctx context.Context, req KfAccountRequest) error {
+	if err := validateKfAccount(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(kfAccountAddPath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// UpdateKfAccount 修改客服昵称
+func (c *Client) UpdateKfAccount(ctx context.Context, req KfAccountRequest) error {
+	if err := validateKfAccount(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(kfAccountUpdatePath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// DeleteKfAccount 删除客服账号
+func (c *Client) DeleteKfAccount(ctx context.Context, req DeleteKfAccountRequest) error {
+	if req.KfAccount == "" {
+		return fmt.Errorf("kf_account is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(kfAccountDeletePath).
+		Query("kf_account", req.KfAccount).
+		Get(ctx)
+	return err
+}
+
+// UploadKfHeadImage 设置客服头像，仅支持 jpg，推荐 640*640
+func (c *Client) UploadKfHeadImage(ctx context.Context, req UploadKfHeadImageRequest) error {
+	if req.KfAccount == "" {
+		return fmt.Errorf("kf_account is required")
+	}
+	file, err := checkUpload(kfHeadImageLimit, req.FileName, req.File)
+	if err != nil {
+		return err
+	}
+
+	_, err = Request[struct{}](c).
+		Path(kfAccountUploadHeadImgPath).
+		Query("kf_account", req.KfAccount).
+		UploadFile(mediaFormField, req.FileName, file).
+		Post(ctx)
+	return err
+}
+
+// GetKfList 获取全部客服账号
+func (c *Client) GetKfList(ctx context.Context) (GetKfListResponse, error) {
+	return Request[GetKfListResponse](c).
+		Path(kfListPath).
+		Get(ctx)
+}
+
+// GetOnlineKfList 获取在线客服及其接待中的会话数
+func (c *Client) GetOnlineKfList(ctx context.Context) (GetOnlineKfListResponse, error) {
+	return Request[GetOnlineKfListResponse](c).
+		Path(kfOnlineListPath).
+		Get(ctx)
+}
+
+// CreateKfSession 为用户创建会话并分配给指定客服，客服需在线
+func (c *Client) CreateKfSession(ctx context.Context, req KfSessionRequest) error {
+	if err := validateKfSession(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(kfSessionCreatePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// CloseKfSession 关闭会话
+func (c *Client) CloseKfSession(ctx context.Context, req KfSessionRequest) error {
+	if err := validateKfSession(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(kfSessionClosePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// GetKfSession 获取用户当前的会话状态
+func (c *Client) GetKfSession(ctx context.Context, req GetKfSessionRequest) (GetKfSessionResponse, error) {
+	if req.OpenID == "" {
+		return GetKfSessionResponse{}, fmt.Errorf("openid is required")
+	}
+
+	return Request[GetKfSessionResponse](c).
+		Path(kfSessionGetPath).
+		Query("openid", req.OpenID).
+		Get(ctx)
+}
+
+// GetKfSessionList 获取客服接待中的会话列表
+func (c *Client) GetKfSessionList(ctx context.Context, req GetKfSessionListRequest) (GetKfSessionListResponse, error) {
+	if req.KfAccount == "" {
+		return GetKfSessionListResponse{}, fmt.Errorf("kf_account is required")
+	}
+
+	return Request[GetKfSessionListResponse](c).
+		Path(kfSessionListPath).
+		Query("kf_account", req.KfAccount).
+		Get(ctx)
+}
+
+// GetKfWaitCase 获取未接入会话列表，最多返回 100 条最早的会话
+func (c *Client) GetKfWaitCase(ctx context.Context) (GetKfWaitCaseResponse, error) {
+	return Request[GetKfWaitCaseResponse](c).
+		Path(kfSessionWaitCasePath).
+		Get(ctx)
+}
+
+// GetMsgRecordList 获取一页客服聊天记录
+func (c *Client) GetMsgRecordList(ctx context.Context, req GetMsgRecordListRequest) (GetMsgRecordListResponse, error) {
+	req, err := normalizeMsgRecordRequest(req)
+	if err != nil {
+		return GetMsgRecordListResponse{}, err
+	}
+
+	return Request[GetMsgRecordListResponse](c).
+		Path(kfMsgRecordListPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// MsgRecords 遍历时间段内的全部客服聊天记录，自动按 msgid 游标翻页。
+// 请求失败时产出一次非 nil 错误并结束遍历。
+func (c *Client) MsgRecords(ctx context.Context, req GetMsgRecordListRequest) iter.Seq2[MsgRecord, error] {
+	return func(yield func(MsgRecord, error) bool) {
+		req, err := normalizeMsgRecordRequest(req)
+		if err != nil {
+			yield(MsgRecord{}, err)
+			return
+		}
+		for {
+			page, err := c.GetMsgRecordList(ctx, req)
+			if err != nil {
+				yield(MsgRecord{}, err)
+				return
+			}
+			for _, record := range page.RecordList {
+				if !yield(record, nil) {
+					return
+				}
+			}
+			// 返回条数小于请求条数说明已取完
+			if len(page.RecordList) == 0 || page.Number < req.Number || page.MsgID == req.MsgID {
+				return
+			}
+			req.MsgID = page.MsgID
+		}
+	}
+}
+
+func normalizeMsgRecordRequest(req GetMsgRecordListRequest) (GetMsgRecordListRequest, error) {
+	if req.StartTime <= 0 || req.EndTime <= req.StartTime {
+		return req, fmt.Errorf("starttime and endtime are required and endtime must be after starttime")
+	}
+	if req.EndTime-req.StartTime > maxMsgRecordSpanSeconds {
+		return req, fmt.Errorf("time range exceeds %d seconds", maxMsgRecordSpanSeconds)
+	}
+	if req.MsgID <= 0 {
+		req.MsgID = 1
+	}
+	if req.Number <= 0 || req.Number > maxMsgRecordNumber {
+		req.Number = maxMsgRecordNumber
+	}
+	return req, nil
+}
+
+func validateKfAccount(req KfAccountRequest) error {
+	if req.KfAccount == "" {
+		return fmt.Errorf("kf_account is required")
+	}
+	if req.Nickname == "" {
+		return fmt.Errorf("nickname is required")
+	}
+	return nil
+}
+
+func validateKfSession(req KfSessionRequest) error {
+	if req.KfAccount == "" {
+		return fmt.Errorf("kf_account is required")
+	}
+	if req.OpenID == "" {
+		return fmt.Errorf("openid is required")
+	}
+	return nil
+}
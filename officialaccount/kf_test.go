@@ -0,0 +1,120 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestKfAccountAndSession(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case kfAccountAddPath:
+			var req KfAccountRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.KfAccount != "test1@kftest" || req.Nickname != "客服1" {
+				t.Errorf("unexpected add request: %+v", req)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case kfAccountDeletePath:
+			if r.Method != http.MethodGet || r.URL.Query().Get("kf_account") != "test1@kftest" {
+				t.Errorf("unexpected delete request: %s %s", r.Method, r.URL)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case kfAccountUploadHeadImgPath:
+			file, header, err := r.FormFile(mediaFormField)
+			if err != nil {
+				t.Errorf("form file: %v", err)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			if r.URL.Query().Get("kf_account") != "test1@kftest" || header.Filename != "head.jpg" || string(data) != "jpeg" {
+				t.Errorf("unexpected head image upload: %s %s %q", r.URL, header.Filename, data)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case kfListPath:
+			_, _ = w.Write([]byte(`{"kf_list":[{"kf_account":"test1@kftest","kf_nick":"ntest1","kf_id":"1001","kf_wx":"kfwx1"}]}`))
+		case kfOnlineListPath:
+			_, _ = w.Write([]byte(`{"kf_online_list":[{"kf_account":"test1@kftest","status":1,"kf_id":"1001","accepted_case":1}]}`))
+		case kfSessionCreatePath:
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case kfSessionGetPath:
+			_, _ = w.Write([]byte(`{"createtime":123456789,"errcode":0,"errmsg":"ok","kf_account":"test1@kftest"}`))
+		case kfSessionWaitCasePath:
+			_, _ = w.Write([]byte(`{"count":1,"waitcaselist":[{"latest_time":123456789,"openid":"o1"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	if err := client.AddKfAccount(ctx, KfAccountRequest{KfAccount: "test1@kftest", Nickname: "客服1"}); err != nil {
+		t.Fatalf("add kf account: %v", err)
+	}
+	if err := client.UploadKfHeadImage(ctx, UploadKfHeadImageRequest{KfAccount: "test1@kftest", FileName: "head.jpg", File: strings.NewReader("jpeg")}); err != nil {
+		t.Fatalf("upload head image: %v", err)
+	}
+	if err := client.UploadKfHeadImage(ctx, UploadKfHeadImageRequest{KfAccount: "test1@kftest", FileName: "head.png", File: strings.NewReader("png")}); err == nil {
+		t.Fatal("expected extension validation error")
+	}
+	list, err := client.GetKfList(ctx)
+	if err != nil || len(list.KfList) != 1 || list.KfList[0].KfWx != "kfwx1" {
+		t.Fatalf("get kf list: %+v %v", list, err)
+	}
+	online, err := client.GetOnlineKfList(ctx)
+	if err != nil || online.KfOnlineList[0].AcceptedCase != 1 {
+		t.Fatalf("get online kf list: %+v %v", online, err)
+	}
+	if err := client.CreateKfSession(ctx, KfSessionRequest{KfAccount: "test1@kftest", OpenID: "o1"}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	session, err := client.GetKfSession(ctx, GetKfSessionRequest{OpenID: "o1"})
+	if err != nil || session.KfAccount != "test1@kftest" || session.CreateTime != 123456789 {
+		t.Fatalf("get session: %+v %v", session, err)
+	}
+	wait, err := client.GetKfWaitCase(ctx)
+	if err != nil || wait.Count != 1 || wait.WaitCaseList[0].OpenID != "o1" {
+		t.Fatalf("get wait case: %+v %v", wait, err)
+	}
+	if err := client.DeleteKfAccount(ctx, DeleteKfAccountRequest{KfAccount: "test1@kftest"}); err != nil {
+		t.Fatalf("delete kf account: %v", err)
+	}
+}
+
+func TestMsgRecordsIterator(t *testing.T) {
+	var cursors []int64
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req GetMsgRecordListRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		cursors = append(cursors, req.MsgID)
+		if req.Number != 2 || req.StartTime != 1700000000 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		switch req.MsgID {
+		case 1:
+			_, _ = w.Write([]byte(`{"recordlist":[{"openid":"o1","opercode":2002,"text":"a","time":1700000001,"worker":"kf1"},{"openid":"o1","opercode":2003,"text":"b","time":1700000002,"worker":"kf1"}],"number":2,"msgid":20165267}`))
+		default:
+			_, _ = w.Write([]byte(`{"recordlist":[{"openid":"o2","opercode":2002,"text":"c","time":1700000003,"worker":"kf2"}],"number":1,"msgid":20165268}`))
+		}
+	})
+
+	var texts []string
+	for record, err := range client.MsgRecords(context.Background(), GetMsgRecordListRequest{StartTime: 1700000000, EndTime: 1700003600, Number: 2}) {
+		if err != nil {
+			t.Fatalf("iterate records: %v", err)
+		}
+		texts = append(texts, record.Text)
+	}
+	if strings.Join(texts, ",") != "a,b,c" || len(cursors) != 2 || cursors[1] != 20165267 {
+		t.Fatalf("unexpected records: %v cursors %v", texts, cursors)
+	}
+
+	for _, err := range client.MsgRecords(context.Background(), GetMsgRecordListRequest{StartTime: 1700000000, EndTime: 1700000000 + maxMsgRecordSpanSeconds + 1}) {
+		if err == nil {
+			t.Fatal("expected time range validation error")
+		}
+	}
+}
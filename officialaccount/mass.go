@@ -0,0 +1,412 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	massSendAllPath       = "/cgi-bin/message/mass/sendall"
+	massSendPath          = "/cgi-bin/message/mass/send"
+	massPreviewPath       = "/cgi-bin/message/mass/preview"
+	massGetPath           = "/cgi-bin/message/mass/get"
+	massDeletePath        = "/cgi-bin/message/mass/delete"
+	massSpeedGetPath      = "/cgi-bin/message/mass/speed/get"
+	massSpeedSetPath      = "/cgi-bin/message/mass/speed/set"
+	maxMassOpenIDs        = 10000
+	minMassOpenIDs        = 2
+	maxMassClientMsgIDLen = 64
+	maxMassSpeed          = 4
+	maxMassImages         = 20
+	massSendSuccessStatus = "send success"
+)
+
+// MassMessage 群发消息内容
+// 由 MassMPNews、MassText、MassVoice、MassImages、MassMPVideo、MassWxCard 实现。
+type MassMessage interface {
+	apply(body *massMessageBody) error
+}
+
+// MassMPNews 图文素材消息
+type MassMPNews struct {
+	MediaID string `json:"media_id"`
+	// SendIgnoreReprint 图文被判定为转载时是否继续群发
+	SendIgnoreReprint bool `json:"-"`
+}
+
+// MassText 文本消息
+type MassText struct {
+	Content string `json:"content"`
+}
+
+// MassVoice 语音消息，MediaID 为永久素材或临时素材
+type MassVoice struct {
+	MediaID string `json:"media_id"`
+}
+
+// MassImages 图片消息，最多 20 张
+type MassImages struct {
+	MediaIDs           []string `json:"media_ids"`
+	Recommend          string   `json:"recommend,omitempty"`
+	NeedOpenComment    int      `json:"need_open_comment,omitempty"`
+	OnlyFansCanComment int      `json:"only_fans_can_comment,omitempty"`
+}
+
+// MassMPVideo 视频消息，MediaID 为素材管理中的视频素材
+type MassMPVideo struct {
+	MediaID string `json:"media_id"`
+}
+
+// MassWxCard 卡券消息
+type MassWxCard struct {
+	CardID string `json:"card_id"`
+}
+
+// MassStatus 群发消息的发送状态
+type MassStatus string
+
+const (
+	MassStatusSending MassStatus = "SENDING"
+	MassStatusSuccess MassStatus = "SEND_SUCCESS"
+	MassStatusFail    MassStatus = "SEND_FAIL"
+	MassStatusDelete  MassStatus = "DELETE"
+)
+
+// MassSendJobFinishEvent 群发结果事件（MASSSENDJOBFINISH），MsgID 与群发接口返回的 msg_id 对应
+type MassSendJobFinishEvent struct {
+	MsgID int64
+	// Status 群发结果，成功为 "send success"，失败为 "send fail" 或 "err(错误码)"
+	Status      string
+	TotalCount  int
+	FilterCount int
+	SentCount   int
+	ErrorCount  int
+	// CopyrightCheckResult 图文原创校验结果，非图文群发时为 nil
+	CopyrightCheckResult *CopyrightCheckResult
+	ArticleURLResult     *ArticleURLResult
+}
+
+// Succeeded 群发是否成功
+func (e MassSendJobFinishEvent) Succeeded() bool {
+	return e.Status == massSendSuccessStatus
+}
+
+type SendMassByTagRequest struct {
+	// IsToAll 为 true 时发送给全部用户，忽略 TagID
+	IsToAll bool
+	TagID   int64
+	Message MassMessage
+	// ClientMsgID 群发幂等 ID，最长 64 字节；24 小时内相同 ID 的群发只会执行一次
+	ClientMsgID string
+}
+
+type SendMassByOpenIDRequest struct {
+	// ToUser 接收者 OpenID 列表，至少 2 个；超过 10000 个时自动拆分为多次群发
+	ToUser  []string
+	Message MassMessage
+	// ClientMsgID 群发幂等 ID，拆分为多次群发时依次追加 _1、_2 等后缀
+	ClientMsgID string
+}
+
+type SendMassResponse struct {
+	MsgID int64 `json:"msg_id"`
+	// MsgDataID 图文消息的数据 ID，用于图文分析数据接口
+	MsgDataID int64 `json:"msg_data_id"`
+}
+
+type SendMassByOpenIDResponse struct {
+	// Results 每次群发的结果，顺序与拆分后的 OpenID 批次一致
+	Results []SendMassResponse
+}
+
+// MsgIDs 返回全部批次的 msg_id
+func (r SendMassByOpenIDResponse) MsgIDs() []int64 {
+	ids := make([]int64, 0, len(r.Results))
+	for _, result := range r.Results {
+		ids = append(ids, result.MsgID)
+	}
+	return ids
+}
+
+type PreviewMassRequest struct {
+	// ToUser 与 ToWxName 二选一，同时设置时优先使用 ToWxName
+	ToUser   string
+	ToWxName string
+	Message  MassMessage
+}
+
+type PreviewMassResponse struct {
+	MsgID int64 `json:"msg_id"`
+}
+
+type GetMassRequest struct {
+	MsgID int64 `json:"msg_id"`
+}
+
+type GetMassResponse struct {
+	MsgID     int64      `json:"msg_id"`
+	MsgStatus MassStatus `json:"msg_status"`
+}
+
+type DeleteMassRequest struct {
+	MsgID int64 `json:"msg_id"`
+	// ArticleIdx 要删除的文章在图文中的位置，从 1 开始；为 0 时删除全部文章
+	ArticleIdx int `json:"article_idx,omitempty"`
+	// URL 要删除的文章 URL，与 MsgID 二选一
+	URL string `json:"url,omitempty"`
+}
+
+// MassSpeed 群发速度，0~4 分别对应 80w/min、60w/min、45w/min、30w/min、10w/min
+type MassSpeed struct {
+	Speed     int `json:"speed"`
+	RealSpeed int `json:"realspeed,omitempty"`
+}
+
+type SetMassSpeedRequest struct {
+	Speed int `json:"speed"`
+}
+
+type massFilter struct {
+	IsToAll bool  `json:"is_to_all"`
+	TagID   int64 `json:"tag_id,omitempty"`
+}
+
+type massMessageBody struct {
+	Filter            *massFilter  `json:"filter,omitempty"`
+	ToUser            any          `json:"touser,omitempty"`
+	ToWxName          string       `json:"towxname,omitempty"`
+	MsgType           string       `json:"msgtype"`
+	MPNews            *MassMPNews  `json:"mpnews,omitempty"`
+	Text              *MassText    `json:"text,omitempty"`
+	Voice             *MassVoice   `json:"voice,omitempty"`
+	Images            *MassImages  `json:"images,omitempty"`
+	MPVideo           *MassMPVideo `json:"mpvideo,omitempty"`
+	WxCard            *MassWxCard  `json:"wxcard,omitempty"`
+	SendIgnoreReprint int          `json:"send_ignore_reprint,omitempty"`
+	ClientMsgID       string       `json:"clientmsgid,omitempty"`
+}
+
+func (m MassMPNews) apply(body *massMessageBody) error {
+	if m.MediaID == "" {
+		return fmt.Errorf("mpnews media_id is required")
+	}
+	body.MsgType, body.MPNews = "mpnews", &m
+	if m.SendIgnoreReprint {
+		body.SendIgnoreReprint = 1
+	}
+	return nil
+}
+
+func (m MassText) apply(body *massMessageBody) error {
+	if m.Content == "" {
+		return fmt.Errorf("text content is required")
+	}
+	body.MsgType, body.Text = "text", &m
+	return nil
+}
+
+func (m MassVoice) apply(body *massMessageBody) error {
+	if m.MediaID == "" {
+		return fmt.Errorf("voice media_id is required")
+	}
+	body.MsgType, body.Voice = "voice", &m
+	return nil
+}
+
+func (m MassImages) apply(body *massMessageBody) error {
+	if len(m.MediaIDs) == 0 {
+		return fmt.Errorf("images media_ids is required")
+	}
+	if len(m.MediaIDs) > maxMassImages {
+		return fmt.Errorf("images media_ids exceeds %d items", maxMassImages)
+	}
+	body.MsgType, body.Images = "image", &m
+	return nil
+}
+
+func (m MassMPVideo) apply(body *massMessageBody) error {
+	if m.MediaID == "" {
+		return fmt.Errorf("mpvideo media_id is required")
+	}
+	body.MsgType, body.MPVideo = "mpvideo", &m
+	return nil
+}
+
+func (m MassWxCard) apply(body *massMessageBody) error {
+	if m.CardID == "" {
+		return fmt.Errorf("wxcard card_id is required")
+	}
+	body.MsgType, body.WxCard = "wxcard", &m
+	return nil
+}
+
+// SendMassByTag 按标签群发，IsToAll 为 true 时发送给全部用户
+func (c *Client) SendMassByTag(ctx context.Context, req SendMassByTagRequest) (SendMassResponse, error) {
+	if !req.IsToAll && req.TagID == 0 {
+		return SendMassResponse{}, fmt.Errorf("tag_id is required unless is_to_all is set")
+	}
+	body, err := newMassMessageBody(req.Message, req.ClientMsgID)
+	if err != nil {
+		return SendMassResponse{}, err
+	}
+	body.Filter = &massFilter{IsToAll: req.IsToAll}
+	if !req.IsToAll {
+		body.Filter.TagID = req.TagID
+	}
+
+	return Request[SendMassResponse](c).
+		Path(massSendAllPath).
+		Body(body).
+		Idempotent(req.ClientMsgID != "").
+		Post(ctx)
+}
+
+// SendMassByOpenID 按 OpenID 列表群发，超过 10000 个时均分为多次群发。
+// 某一批次失败时返回已成功批次的结果与错误，调用方可据此只重发剩余批次。
+func (c *Client) SendMassByOpenID(ctx context.Context, req SendMassByOpenIDRequest) (SendMassByOpenIDResponse, error) {
+	if len(req.ToUser) < minMassOpenIDs {
+		return SendMassByOpenIDResponse{}, fmt.Errorf("touser requires at least %d openids", minMassOpenIDs)
+	}
+	chunks := chunkMassOpenIDs(req.ToUser)
+	if req.ClientMsgID != "" && len(chunks) > 1 {
+		if suffixed := fmt.Sprintf("%s_%d", req.ClientMsgID, len(chunks)); len(suffixed) > maxMassClientMsgIDLen {
+			return SendMassByOpenIDResponse{}, fmt.Errorf("clientmsgid with batch suffix exceeds %d bytes", maxMassClientMsgIDLen)
+		}
+	}
+	body, err := newMassMessageBody(req.Message, req.ClientMsgID)
+	if err != nil {
+		return SendMassByOpenIDResponse{}, err
+	}
+
+	var out SendMassByOpenIDResponse
+	for i, chunk := range chunks {
+		body.ToUser = chunk
+		if req.ClientMsgID != "" && len(chunks) > 1 {
+			body.ClientMsgID = fmt.Sprintf("%s_%d", req.ClientMsgID, i+1)
+		}
+		resp, err := Request[SendMassResponse](c).
+			Path(massSendPath).
+			Body(body).
+			Idempotent(req.ClientMsgID != "").
+			Post(ctx)
+		if err != nil {
+			return out, fmt.Errorf("send mass batch %d/%d: %w", i+1, len(chunks), err)
+		}
+		out.Results = append(out.Results, resp)
+	}
+	return out, nil
+}
+
+// PreviewMass 预览群发消息，发送给指定用户，每日限 100 次
+func (c *Client) PreviewMass(ctx context.Context, req PreviewMassRequest) (PreviewMassResponse, error) {
+	if req.ToUser == "" && req.ToWxName == "" {
+		return PreviewMassResponse{}, fmt.Errorf("touser or towxname is required")
+	}
+	body, err := newMassMessageBody(req.Message, "")
+	if err != nil {
+		return PreviewMassResponse{}, err
+	}
+	if req.ToWxName != "" {
+		body.ToWxName = req.ToWxName
+	} else {
+		body.ToUser = req.ToUser
+	}
+
+	return Request[PreviewMassResponse](c).
+		Path(massPreviewPath).
+		Body(body).
+		Post(ctx)
+}
+
+// GetMass 查询群发消息的发送状态
+func (c *Client) GetMass(ctx context.Context, req GetMassRequest) (GetMassResponse, error) {
+	if req.MsgID == 0 {
+		return GetMassResponse{}, fmt.Errorf("msg_id is required")
+	}
+
+	return Request[GetMassResponse](c).
+		Path(massGetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// DeleteMass 删除群发消息，只能删除图文与视频消息，群发半小时内无法删除
+func (c *Client) DeleteMass(ctx context.Context, req DeleteMassRequest) error {
+	if req.MsgID == 0 && req.URL == "" {
+		return fmt.Errorf("msg_id or url is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(massDeletePath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// GetMassSpeed 获取群发速度
+func (c *Client) GetMassSpeed(ctx context.Context) (MassSpeed, error) {
+	return Request[MassSpeed](c).
+		Path(massSpeedGetPath).
+		Body(struct{}{}).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// SetMassSpeed 设置群发速度，取值 0~4，数值越大速度越慢
+func (c *Client) SetMassSpeed(ctx context.Context, req SetMassSpeedRequest) error {
+	if req.Speed < 0 || req.Speed > maxMassSpeed {
+		return fmt.Errorf("speed must be between 0 and %d", maxMassSpeed)
+	}
+
+	_, err := Request[struct{}](c).
+		Path(massSpeedSetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// MassSendJobFinish 将群发结果事件转换为 MassSendJobFinishEvent，其他消息返回 false
+func (m *Message) MassSendJobFinish() (MassSendJobFinishEvent, bool) {
+	if !m.IsEvent() || m.Event != EventMassSendJobFinish {
+		return MassSendJobFinishEvent{}, false
+	}
+	return MassSendJobFinishEvent{
+		MsgID:                m.EventMsgID,
+		Status:               m.Status,
+		TotalCount:           m.TotalCount,
+		FilterCount:          m.FilterCount,
+		SentCount:            m.SentCount,
+		ErrorCount:           m.ErrorCount,
+		CopyrightCheckResult: m.CopyrightCheckResult,
+		ArticleURLResult:     m.ArticleURLResult,
+	}, true
+}
+
+func newMassMessageBody(msg MassMessage, clientMsgID string) (massMessageBody, error) {
+	if msg == nil {
+		return massMessageBody{}, fmt.Errorf("message is required")
+	}
+	if len(clientMsgID) > maxMassClientMsgIDLen {
+		return massMessageBody{}, fmt.Errorf("clientmsgid exceeds %d bytes", maxMassClientMsgIDLen)
+	}
+	body := massMessageBody{ClientMsgID: clientMsgID}
+	if err := msg.apply(&body); err != nil {
+		return massMessageBody{}, err
+	}
+	return body, nil
+}
+
+// chunkMassOpenIDs 将 OpenID 均分为每批不超过 10000 个，避免最后一批不足 2 个导致群发失败
+func chunkMassOpenIDs(openIDs []string) [][]string {
+	n := (len(openIDs) + maxMassOpenIDs - 1) / maxMassOpenIDs
+	chunks := make([][]string, 0, n)
+	for i := range n {
+		start := i * len(openIDs) / n
+		end := (i + 1) * len(openIDs) / n
+		chunks = append(chunks, openIDs[start:end])
+	}
+	return chunks
+}
@@ -0,0 +1,189 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestSendMassByTag(t *testing.T) {
+	var got map[string]any
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != massSendAllPath {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"send job submission success","msg_id":34182,"msg_data_id":206227730}`))
+	})
+
+	resp, err := client.SendMassByTag(context.Background(), SendMassByTagRequest{
+		TagID:       2,
+		Message:     MassMPNews{MediaID: "media-1", SendIgnoreReprint: true},
+		ClientMsgID: "campaign-1",
+	})
+	if err != nil || resp.MsgID != 34182 || resp.MsgDataID != 206227730 {
+		t.Fatalf("send mass by tag: %+v %v", resp, err)
+	}
+	filter := got["filter"].(map[string]any)
+	if filter["is_to_all"] != false || filter["tag_id"] != float64(2) {
+		t.Fatalf("unexpected filter: %v", filter)
+	}
+	if got["msgtype"] != "mpnews" || got["send_ignore_reprint"] != float64(1) || got["clientmsgid"] != "campaign-1" {
+		t.Fatalf("unexpected body: %v", got)
+	}
+	if mpnews := got["mpnews"].(map[string]any); len(mpnews) != 1 || mpnews["media_id"] != "media-1" {
+		t.Fatalf("unexpected mpnews: %v", mpnews)
+	}
+
+	if _, err := client.SendMassByTag(context.Background(), SendMassByTagRequest{Message: MassText{Content: "hi"}}); err == nil {
+		t.Fatal("expected tag_id validation error")
+	}
+	images := MassImages{MediaIDs: make([]string, maxMassImages+1)}
+	if _, err := client.SendMassByTag(context.Background(), SendMassByTagRequest{IsToAll: true, Message: images}); err == nil {
+		t.Fatal("expected images count validation error")
+	}
+}
+
+func TestSendMassByOpenIDChunks(t *testing.T) {
+	var sizes []int
+	var clientMsgIDs []string
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ToUser      []string `json:"touser"`
+			MsgType     string   `json:"msgtype"`
+			ClientMsgID string   `json:"clientmsgid"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != massSendPath || body.MsgType != "text" {
+			t.Errorf("unexpected request: %s %+v", r.URL.Path, body.MsgType)
+		}
+		sizes = append(sizes, len(body.ToUser))
+		clientMsgIDs = append(clientMsgIDs, body.ClientMsgID)
+		if len(sizes) == 3 {
+			_, _ = w.Write([]byte(`{"errcode":45028,"errmsg":"has no masssend quota"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"errcode":0,"errmsg":"ok","msg_id":%d}`, 1000+len(sizes))
+	})
+
+	openIDs := make([]string, 2*maxMassOpenIDs+1)
+	for i := range openIDs {
+		openIDs[i] = fmt.Sprintf("o%d", i)
+	}
+	resp, err := client.SendMassByOpenID(context.Background(), SendMassByOpenIDRequest{
+		ToUser:      openIDs,
+		Message:     MassText{Content: "hi"},
+		ClientMsgID: "campaign-2",
+	})
+	if err == nil {
+		t.Fatal("expected error from third batch")
+	}
+	if !slices.Equal(sizes, []int{6667, 6667, 6667}) {
+		t.Fatalf("unexpected batch sizes: %v", sizes)
+	}
+	if !slices.Equal(clientMsgIDs, []string{"campaign-2_1", "campaign-2_2", "campaign-2_3"}) {
+		t.Fatalf("unexpected clientmsgids: %v", clientMsgIDs)
+	}
+	if !slices.Equal(resp.MsgIDs(), []int64{1001, 1002}) {
+		t.Fatalf("expected results of successful batches, got %v", resp.MsgIDs())
+	}
+
+	if _, err := client.SendMassByOpenID(context.Background(), SendMassByOpenIDRequest{ToUser: []string{"o1"}, Message: MassText{Content: "hi"}}); err == nil {
+		t.Fatal("expected touser validation error")
+	}
+}
+
+func TestChunkMassOpenIDs(t *testing.T) {
+	for _, n := range []int{2, maxMassOpenIDs, maxMassOpenIDs + 1, 3*maxMassOpenIDs - 1} {
+		chunks := chunkMassOpenIDs(make([]string, n))
+		total := 0
+		for _, chunk := range chunks {
+			if len(chunk) < minMassOpenIDs || len(chunk) > maxMassOpenIDs {
+				t.Fatalf("n=%d: invalid chunk size %d", n, len(chunk))
+			}
+			total += len(chunk)
+		}
+		if total != n {
+			t.Fatalf("n=%d: chunks cover %d openids", n, total)
+		}
+	}
+}
+
+func TestMassManagement(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case massPreviewPath:
+			if body["towxname"] != "wxid" || body["touser"] != nil || body["msgtype"] != "image" {
+				t.Errorf("unexpected preview body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"preview success","msg_id":34182}`))
+		case massGetPath:
+			_, _ = w.Write([]byte(`{"msg_id":201053012,"msg_status":"SEND_SUCCESS"}`))
+		case massDeletePath:
+			if body["msg_id"] != float64(30124) || body["article_idx"] != float64(2) {
+				t.Errorf("unexpected delete body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case massSpeedGetPath:
+			_, _ = w.Write([]byte(`{"speed":3,"realspeed":15}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	preview, err := client.PreviewMass(ctx, PreviewMassRequest{ToUser: "o1", ToWxName: "wxid", Message: MassImages{MediaIDs: []string{"m1"}}})
+	if err != nil || preview.MsgID != 34182 {
+		t.Fatalf("preview: %+v %v", preview, err)
+	}
+	status, err := client.GetMass(ctx, GetMassRequest{MsgID: 201053012})
+	if err != nil || status.MsgStatus != MassStatusSuccess {
+		t.Fatalf("get mass: %+v %v", status, err)
+	}
+	if err := client.DeleteMass(ctx, DeleteMassRequest{MsgID: 30124, ArticleIdx: 2}); err != nil {
+		t.Fatalf("delete mass: %v", err)
+	}
+	speed, err := client.GetMassSpeed(ctx)
+	if err != nil || speed.Speed != 3 || speed.RealSpeed != 15 {
+		t.Fatalf("get speed: %+v %v", speed, err)
+	}
+	if err := client.SetMassSpeed(ctx, SetMassSpeedRequest{Speed: maxMassSpeed + 1}); err == nil {
+		t.Fatal("expected speed validation error")
+	}
+}
+
+func TestMassSendJobFinishEvent(t *testing.T) {
+	body := `<xml><ToUserName><![CDATA[gh_1]]></ToUserName><FromUserName><![CDATA[o1]]></FromUserName><CreateTime>1394524295</CreateTime>
+<MsgType><![CDATA[event]]></MsgType><Event><![CDATA[MASSSENDJOBFINISH]]></Event><MsgID>1988</MsgID>
+<Status><![CDATA[send success]]></Status><TotalCount>100</TotalCount><FilterCount>80</FilterCount><SentCount>75</SentCount><ErrorCount>5</ErrorCount>
+<CopyrightCheckResult><Count>1</Count><ResultList><item><ArticleIdx>1</ArticleIdx><UserDeclareState>0</UserDeclareState><AuditState>2</AuditState>
+<OriginalArticleUrl><![CDATA[Url_1]]></OriginalArticleUrl><OriginalArticleType>1</OriginalArticleType><CanReprint>1</CanReprint>
+<NeedReplaceContent>1</NeedReplaceContent><NeedShowReprintSource>1</NeedShowReprintSource></item></ResultList><CheckState>2</CheckState></CopyrightCheckResult>
+<ArticleUrlResult><Count>1</Count><ResultList><item><ArticleIdx>1</ArticleIdx><ArticleUrl><![CDATA[https://mp.weixin.qq.com/s/1]]></ArticleUrl></item></ResultList></ArticleUrlResult></xml>`
+	var msg Message
+	if err := xml.Unmarshal([]byte(body), &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	event, ok := msg.MassSendJobFinish()
+	if !ok || !event.Succeeded() || event.MsgID != 1988 || event.SentCount != 75 || event.ErrorCount != 5 {
+		t.Fatalf("unexpected event: %+v %v", event, ok)
+	}
+	if event.CopyrightCheckResult.CheckState != 2 || event.CopyrightCheckResult.ResultList[0].OriginalArticleURL != "Url_1" {
+		t.Fatalf("unexpected copyright result: %+v", event.CopyrightCheckResult)
+	}
+	if event.ArticleURLResult.ResultList[0].ArticleURL != "https://mp.weixin.qq.com/s/1" {
+		t.Fatalf("unexpected article urls: %+v", event.ArticleURLResult)
+	}
+
+	if _, ok := (&Message{MsgType: MsgTypeEvent, Event: EventTemplateSendJobFinish}).MassSendJobFinish(); ok {
+		t.Fatal("template event should not convert")
+	}
+}
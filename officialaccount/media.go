@@ -0,0 +1,331 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+const (
+	mediaUploadPath          = "/cgi-bin/media/upload"
+	mediaGetPath             = "/cgi-bin/media/get"
+	mediaUploadImgPath       = "/cgi-bin/media/uploadimg"
+	materialAddPath          = "/cgi-bin/material/add_material"
+	materialGetPath          = "/cgi-bin/material/get_material"
+	materialDeletePath       = "/cgi-bin/material/del_material"
+	materialCountPath        = "/cgi-bin/material/get_materialcount"
+	materialBatchGetPath     = "/cgi-bin/material/batchget_material"
+	mediaFormField           = "media"
+	maxBatchGetMaterialCount = 20
+)
+
+// MediaType 素材类型
+type MediaType string
+
+const (
+	MediaTypeImage MediaType = "image"
+	MediaTypeVoice MediaType = "voice"
+	MediaTypeVideo MediaType = "video"
+	MediaTypeThumb MediaType = "thumb"
+	// MediaTypeNews 图文素材，仅用于 BatchGetMaterial
+	MediaTypeNews MediaType = "news"
+)
+
+// mediaLimit 素材的格式与大小限制
+type mediaLimit struct {
+	exts    []string
+	maxSize int64
+}
+
+var (
+	mediaLimits = map[MediaType]mediaLimit{
+		MediaTypeImage: {exts: []string{".bmp", ".png", ".jpeg", ".jpg", ".gif"}, maxSize: 10 << 20},
+		MediaTypeVoice: {exts: []string{".amr", ".mp3"}, maxSize: 2 << 20},
+		MediaTypeVideo: {exts: []string{".mp4"}, maxSize: 10 << 20},
+		MediaTypeThumb: {exts: []string{".jpg", ".jpeg"}, maxSize: 64 << 10},
+	}
+	// 图文消息内的图片
+	articleImageLimit = mediaLimit{exts: []string{".jpg", ".jpeg", ".png"}, maxSize: 1 << 20}
+)
+
+// ErrMediaTooLarge 上传的文件超过微信限制的大小
+var ErrMediaTooLarge = errors.New("media exceeds size limit")
+
+type UploadMediaRequest struct {
+	Type MediaType
+	// FileName 文件名，扩展名用于校验格式
+	FileName string
+	File     io.Reader
+}
+
+type UploadMediaResponse struct {
+	Type         MediaType `json:"type"`
+	MediaID      string    `json:"media_id"`
+	ThumbMediaID string    `json:"thumb_media_id"`
+	CreatedAt    int64     `json:"created_at"`
+}
+
+type GetMediaRequest struct {
+	MediaID string
+}
+
+type UploadImageRequest struct {
+	FileName string
+	File     io.Reader
+}
+
+type UploadImageResponse struct {
+	URL string `json:"url"`
+}
+
+type AddMaterialRequest struct {
+	Type     MediaType
+	FileName string
+	File     io.Reader
+	// Title 视频素材标题，Type 为 video 时必填
+	Title string
+	// Introduction 视频素材描述
+	Introduction string
+}
+
+type AddMaterialResponse struct {
+	MediaID string `json:"media_id"`
+	// URL 图片素材的地址，仅 image 类型返回
+	URL string `json:"url"`
+}
+
+type GetMaterialRequest struct {
+	MediaID string `json:"media_id"`
+}
+
+type DeleteMaterialRequest struct {
+	MediaID string `json:"media_id"`
+}
+
+type GetMaterialCountResponse struct {
+	VoiceCount int `json:"voice_count"`
+	VideoCount int `json:"video_count"`
+	ImageCount int `json:"image_count"`
+	NewsCount  int `json:"news_count"`
+}
+
+type BatchGetMaterialRequest struct {
+	Type   MediaType `json:"type"`
+	Offset int       `json:"offset"`
+	// Count 返回数量，取值 1~20，<= 0 时使用 20
+	Count int `json:"count"`
+}
+
+type MaterialItem struct {
+	MediaID    string `json:"media_id"`
+	Name       string `json:"name"`
+	UpdateTime int64  `json:"update_time"`
+	URL        string `json:"url"`
+	// Content 图文素材的内容，其他类型为空
+	Content json.RawMessage `json:"content,omitempty"`
+}
+
+type BatchGetMaterialResponse struct {
+	TotalCount int            `json:"total_count"`
+	ItemCount  int            `json:"item_count"`
+	Item       []MaterialItem `json:"item"`
+}
+
+// UploadMedia 上传临时素材，素材保存 3 天
+func (c *Client) UploadMedia(ctx context.Context, req UploadMediaRequest) (UploadMediaResponse, error) {
+	file, err := checkMediaFile(req.Type, req.FileName, req.File)
+	if err != nil {
+		return UploadMediaResponse{}, err
+	}
+
+	return Request[UploadMediaResponse](c).
+		Path(mediaUploadPath).
+		Query("type", string(req.Type)).
+		UploadFile(mediaFormField, req.FileName, file).
+		Post(ctx)
+}
+
+// GetMedia 下载临时素材，调用方负责关闭返回的 Body。
+// 视频素材返回 JSON（包含 video_url），其余类型返回文件内容。
+func (c *Client) GetMedia(ctx context.Context, req GetMediaRequest) (*core.StreamResponse, error) {
+	if req.MediaID == "" {
+		return nil, fmt.Errorf("media_id is required")
+	}
+
+	return c.apiClient.Request().
+		Path(mediaGetPath).
+		Query("media_id", req.MediaID).
+		GetStream(ctx)
+}
+
+// UploadImage 上传图文消息内的图片，返回的 URL 仅可用于图文消息正文
+func (c *Client) UploadImage(ctx context.Context, req UploadImageRequest) (UploadImageResponse, error) {
+	file, err := checkUpload(articleImageLimit, req.FileName, req.File)
+	if err != nil {
+		return UploadImageResponse{}, err
+	}
+
+	return Request[UploadImageResponse](c).
+		Path(mediaUploadImgPath).
+		UploadFile(mediaFormField, req.FileName, file).
+		Post(ctx)
+}
+
+// AddMaterial 新增永久素材，视频素材需要同时提交标题与描述
+func (c *Client) AddMaterial(ctx context.Context, req AddMaterialRequest) (AddMaterialResponse, error) {
+	file, err := checkMediaFile(req.Type, req.FileName, req.File)
+	if err != nil {
+		return AddMaterialResponse{}, err
+	}
+
+	builder := Request[AddMaterialResponse](c).
+		Path(materialAddPath).
+		Query("type", string(req.Type)).
+		UploadFile(mediaFormField, req.FileName, file)
+
+	if req.Type == MediaTypeVideo {
+		if req.Title == "" {
+			return AddMaterialResponse{}, fmt.Errorf("title is required for video material")
+		}
+		description, err := json.Marshal(map[string]string{
+			"title":        req.Title,
+			"introduction": req.Introduction,
+		})
+		if err != nil {
+			return AddMaterialResponse{}, fmt.Errorf("marshal description: %w", err)
+		}
+		builder.UploadField("description", string(description))
+	}
+
+	return builder.Post(ctx)
+}
+
+// GetMaterial 获取永久素材，调用方负责关闭返回的 Body。
+// 图文与视频素材返回 JSON，其余类型返回文件内容。
+func (c *Client) GetMaterial(ctx context.Context, req GetMaterialRequest) (*core.StreamResponse, error) {
+	if req.MediaID == "" {
+		return nil, fmt.Errorf("media_id is required")
+	}
+
+	return c.apiClient.Request().
+		Path(materialGetPath).
+		Body(req).
+		Idempotent(true).
+		PostStream(ctx)
+}
+
+// DeleteMaterial 删除永久素材
+func (c *Client) DeleteMaterial(ctx context.Context, req DeleteMaterialRequest) error {
+	if req.MediaID == "" {
+		return fmt.Errorf("media_id is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(materialDeletePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// GetMaterialCount 获取永久素材总数
+func (c *Client) GetMaterialCount(ctx context.Context) (GetMaterialCountResponse, error) {
+	return Request[GetMaterialCountResponse](c).
+		Path(materialCountPath).
+		Get(ctx)
+}
+
+// BatchGetMaterial 分页获取永久素材列表
+func (c *Client) BatchGetMaterial(ctx context.Context, req BatchGetMaterialRequest) (BatchGetMaterialResponse, error) {
+	switch req.Type {
+	case MediaTypeImage, MediaTypeVoice, MediaTypeVideo, MediaTypeNews:
+	default:
+		return BatchGetMaterialResponse{}, fmt.Errorf("invalid material type: %q", req.Type)
+	}
+	if req.Count <= 0 || req.Count > maxBatchGetMaterialCount {
+		req.Count = maxBatchGetMaterialCount
+	}
+
+	return Request[BatchGetMaterialResponse](c).
+		Path(materialBatchGetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+func checkMediaFile(mediaType MediaType, fileName string, file io.Reader) (io.Reader, error) {
+	limit, ok := mediaLimits[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("invalid media type: %q", mediaType)
+	}
+	return checkUpload(limit, fileName, file)
+}
+
+// checkUpload 上传前校验扩展名与大小。
+// 能获取大小的 Reader（bytes.Reader、strings.Reader、*os.File 等）直接校验，其余在读取超限时中止上传。
+func checkUpload(limit mediaLimit, fileName string, file io.Reader) (io.Reader, error) {
+	if file == nil {
+		return nil, fmt.Errorf("file is required")
+	}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if !slices.Contains(limit.exts, ext) {
+		return nil, fmt.Errorf("unsupported file extension %q, expected one of %s", ext, strings.Join(limit.exts, ", "))
+	}
+
+	if size, ok := readerSize(file); ok {
+		if size > limit.maxSize {
+			return nil, fmt.Errorf("%w: %s is %d bytes, limit %d", ErrMediaTooLarge, fileName, size, limit.maxSize)
+		}
+		return file, nil
+	}
+	return &limitedUpload{r: file, name: fileName, remaining: limit.maxSize, limit: limit.maxSize}, nil
+}
+
+func readerSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len()), true
+	case interface{ Stat() (fs.FileInfo, error) }:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		// 已读取部分内容的文件只会上传剩余部分
+		if seeker, ok := r.(io.Seeker); ok {
+			offset, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return 0, false
+			}
+			return max(info.Size()-offset, 0), true
+		}
+		return info.Size(), true
+	}
+	return 0, false
+}
+
+// limitedUpload 读取超过 limit 字节时返回 ErrMediaTooLarge
+type limitedUpload struct {
+	r         io.Reader
+	name      string
+	remaining int64
+	limit     int64
+}
+
+func (l *limitedUpload) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) > l.remaining {
+		return int(l.remaining), fmt.Errorf("%w: %s exceeds %d bytes", ErrMediaTooLarge, l.name, l.limit)
+	}
+	l.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,177 @@
+package officialaccount
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// onlyReader 隐藏底层类型，模拟无法预知大小的 Reader
+type onlyReader struct{ io.Reader }
+
+func TestUploadMedia(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != mediaUploadPath {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("type") != "image" {
+			t.Errorf("unexpected type: %s", r.URL.Query().Get("type"))
+		}
+		file, header, err := r.FormFile(mediaFormField)
+		if err != nil {
+			t.Errorf("form file: %v", err)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		if header.Filename != "a.jpg" || string(data) != "jpeg" {
+			t.Errorf("unexpected file: %s %q", header.Filename, data)
+		}
+		_, _ = w.Write([]byte(`{"type":"image","media_id":"media-1","created_at":1700000000}`))
+	})
+	ctx := context.Background()
+
+	resp, err := client.UploadMedia(ctx, UploadMediaRequest{Type: MediaTypeImage, FileName: "a.jpg", File: strings.NewReader("jpeg")})
+	if err != nil || resp.MediaID != "media-1" {
+		t.Fatalf("upload media: %+v %v", resp, err)
+	}
+
+	tests := []struct {
+		name    string
+		req     UploadMediaRequest
+		wantErr error
+	}{
+		{name: "invalid type", req: UploadMediaRequest{Type: "file", FileName: "a.jpg", File: strings.NewReader("x")}},
+		{name: "invalid extension", req: UploadMediaRequest{Type: MediaTypeVoice, FileName: "a.wav", File: strings.NewReader("x")}},
+		{name: "known size too large", req: UploadMediaRequest{Type: MediaTypeThumb, FileName: "a.jpg", File: bytes.NewReader(make([]byte, 64<<10+1))}, wantErr: ErrMediaTooLarge},
+		{name: "streamed size too large", req: UploadMediaRequest{Type: MediaTypeThumb, FileName: "a.jpg", File: onlyReader{bytes.NewReader(make([]byte, 64<<10+1))}}, wantErr: ErrMediaTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.UploadMedia(ctx, tt.req)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReaderSizePartiallyReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thumb.jpg")
+	if err := os.WriteFile(path, make([]byte, 64<<10+10), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open file: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(20, io.SeekStart); err != nil {
+		t.Fatalf("seek: %v", err)
+	}
+
+	size, ok := readerSize(f)
+	if !ok || size != 64<<10-10 {
+		t.Fatalf("expected remaining size %d, got %d %v", 64<<10-10, size, ok)
+	}
+	// 剩余部分未超过缩略图限制，应通过大小校验
+	if _, err := checkMediaFile(MediaTypeThumb, "thumb.jpg", f); err != nil {
+		t.Fatalf("check partially read file: %v", err)
+	}
+}
+
+func TestAddVideoMaterial(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != materialAddPath {
+			http.NotFound(w, r)
+			return
+		}
+		var description map[string]string
+		if err := json.Unmarshal([]byte(r.FormValue("description")), &description); err != nil {
+			t.Errorf("decode description: %v", err)
+		}
+		if description["title"] != "标题" || description["introduction"] != "简介" {
+			t.Errorf("unexpected description: %v", description)
+		}
+		_, _ = w.Write([]byte(`{"media_id":"video-1"}`))
+	})
+	ctx := context.Background()
+
+	resp, err := client.AddMaterial(ctx, AddMaterialRequest{
+		Type:         MediaTypeVideo,
+		FileName:     "v.mp4",
+		File:         strings.NewReader("mp4"),
+		Title:        "标题",
+		Introduction: "简介",
+	})
+	if err != nil || resp.MediaID != "video-1" {
+		t.Fatalf("add material: %+v %v", resp, err)
+	}
+
+	if _, err := client.AddMaterial(ctx, AddMaterialRequest{Type: MediaTypeVideo, FileName: "v.mp4", File: strings.NewReader("mp4")}); err == nil {
+		t.Fatal("expected title validation error")
+	}
+}
+
+func TestGetMediaAndMaterial(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case mediaGetPath:
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("Content-Disposition", `attachment; filename="media-1.jpg"`)
+			_, _ = w.Write([]byte("jpeg-data"))
+		case materialGetPath:
+			_, _ = w.Write([]byte(`{"errcode":40007,"errmsg":"invalid media_id"}`))
+		case materialCountPath:
+			_, _ = w.Write([]byte(`{"voice_count":1,"video_count":2,"image_count":3,"news_count":4}`))
+		case materialBatchGetPath:
+			var req BatchGetMaterialRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Count != maxBatchGetMaterialCount {
+				t.Errorf("unexpected count: %d", req.Count)
+			}
+			_, _ = w.Write([]byte(`{"total_count":1,"item_count":1,"item":[{"media_id":"m1","name":"a.jpg","update_time":1,"url":"u"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	stream, err := client.GetMedia(ctx, GetMediaRequest{MediaID: "media-1"})
+	if err != nil {
+		t.Fatalf("get media: %v", err)
+	}
+	data, _ := io.ReadAll(stream.Body)
+	stream.Body.Close()
+	if string(data) != "jpeg-data" || stream.Filename != "media-1.jpg" {
+		t.Fatalf("unexpected media: %q %s", data, stream.Filename)
+	}
+
+	if _, err := client.GetMaterial(ctx, GetMaterialRequest{MediaID: "missing"}); err == nil {
+		t.Fatal("expected wechat error")
+	}
+
+	count, err := client.GetMaterialCount(ctx)
+	if err != nil || count.NewsCount != 4 {
+		t.Fatalf("get material count: %+v %v", count, err)
+	}
+
+	list, err := client.BatchGetMaterial(ctx, BatchGetMaterialRequest{Type: MediaTypeImage})
+	if err != nil || list.Item[0].MediaID != "m1" {
+		t.Fatalf("batch get material: %+v %v", list, err)
+	}
+	if _, err := client.BatchGetMaterial(ctx, BatchGetMaterialRequest{Type: MediaTypeThumb}); err == nil {
+		t.Fatal("expected type validation error")
+	}
+}
@@ -0,0 +1,290 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	menuCreatePath            = "/cgi-bin/menu/create"
+	menuDeletePath            = "/cgi-bin/menu/delete"
+	menuAddConditionalPath    = "/cgi-bin/menu/addconditional"
+	menuDelConditionalPath    = "/cgi-bin/menu/delconditional"
+	menuTryMatchPath          = "/cgi-bin/menu/trymatch"
+	currentSelfMenuInfoPath   = "/cgi-bin/get_current_selfmenu_info"
+	maxMenuButtons            = 3
+	maxMenuSubButtons         = 5
+	maxMenuButtonNameBytes    = 16
+	maxMenuSubButtonNameBytes = 60
+	maxMenuButtonKeyBytes     = 128
+	maxMenuButtonURLBytes     = 1024
+)
+
+// ButtonType 自定义菜单按钮类型
+type ButtonType string
+
+const (
+	ButtonTypeClick              ButtonType = "click"
+	ButtonTypeView               ButtonType = "view"
+	ButtonTypeMiniprogram        ButtonType = "miniprogram"
+	ButtonTypeScanCodePush       ButtonType = "scancode_push"
+	ButtonTypeScanCodeWaitMsg    ButtonType = "scancode_waitmsg"
+	ButtonTypePicSysPhoto        ButtonType = "pic_sysphoto"
+	ButtonTypePicPhotoOrAlbum    ButtonType = "pic_photo_or_album"
+	ButtonTypePicWeixin          ButtonType = "pic_weixin"
+	ButtonTypeLocationSelect     ButtonType = "location_select"
+	ButtonTypeMediaID            ButtonType = "media_id"
+	ButtonTypeViewLimited        ButtonType = "view_limited"
+	ButtonTypeArticleID          ButtonType = "article_id"
+	ButtonTypeArticleViewLimited ButtonType = "article_view_limited"
+)
+
+// Button 自定义菜单按钮
+// 一级菜单包含 SubButtons 时作为子菜单容器，不设置 Type。
+type Button struct {
+	Type       ButtonType `json:"type,omitempty"`
+	Name       string     `json:"name"`
+	Key        string     `json:"key,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	AppID      string     `json:"appid,omitempty"`
+	PagePath   string     `json:"pagepath,omitempty"`
+	MediaID    string     `json:"media_id,omitempty"`
+	ArticleID  string     `json:"article_id,omitempty"`
+	SubButtons []Button   `json:"sub_button,omitempty"`
+}
+
+// MenuMatchRule 个性化菜单匹配规则，至少设置一项
+type MenuMatchRule struct {
+	TagID string `json:"tag_id,omitempty"`
+	// ClientPlatformType 客户端版本：1 为 iOS，2 为 Android，3 为其他
+	ClientPlatformType string `json:"client_platform_type,omitempty"`
+}
+
+type CreateMenuRequest struct {
+	Buttons []Button `json:"button"`
+}
+
+type AddConditionalMenuRequest struct {
+	Buttons   []Button      `json:"button"`
+	MatchRule MenuMatchRule `json:"matchrule"`
+}
+
+type AddConditionalMenuResponse struct {
+	MenuID string `json:"menuid"`
+}
+
+type DeleteConditionalMenuRequest struct {
+	MenuID string `json:"menuid"`
+}
+
+type TryMatchMenuRequest struct {
+	// UserID 粉丝的 OpenID 或微信号
+	UserID string `json:"user_id"`
+}
+
+type TryMatchMenuResponse struct {
+	Buttons []Button `json:"button"`
+}
+
+// GetCurrentSelfMenuInfoResponse 当前自定义菜单配置，包含通过公众平台官网设置的菜单
+type GetCurrentSelfMenuInfoResponse struct {
+	IsMenuOpen   int `json:"is_menu_open"`
+	SelfMenuInfo struct {
+		Buttons []SelfMenuButton `json:"button"`
+	} `json:"selfmenu_info"`
+}
+
+// SelfMenuButton 查询接口返回的菜单按钮
+// 官网设置的菜单可能为 text、img、voice、video、news 等类型，内容位于 Value 或 NewsInfo。
+type SelfMenuButton struct {
+	Type      string              `json:"type"`
+	Name      string              `json:"name"`
+	Key       string              `json:"key"`
+	URL       string              `json:"url"`
+	Value     string              `json:"value"`
+	AppID     string              `json:"appid"`
+	PagePath  string              `json:"pagepath"`
+	ArticleID string              `json:"article_id"`
+	SubButton *SelfMenuSubButtons `json:"sub_button"`
+	NewsInfo  *SelfMenuNewsInfo   `json:"news_info"`
+}
+
+type SelfMenuSubButtons struct {
+	List []SelfMenuButton `json:"list"`
+}
+
+type SelfMenuNewsInfo struct {
+	List []SelfMenuNews `json:"list"`
+}
+
+type SelfMenuNews struct {
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	Digest     string `json:"digest"`
+	ShowCover  int    `json:"show_cover"`
+	CoverURL   string `json:"cover_url"`
+	ContentURL string `json:"content_url"`
+	SourceURL  string `json:"source_url"`
+}
+
+// CreateMenu 创建自定义菜单，发送前校验菜单结构
+func (c *Client) CreateMenu(ctx context.Context, req CreateMenuRequest) error {
+	if err := validateMenuButtons(req.Buttons); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(menuCreatePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// GetCurrentSelfMenuInfo 查询当前自定义菜单配置
+func (c *Client) GetCurrentSelfMenuInfo(ctx context.Context) (GetCurrentSelfMenuInfoResponse, error) {
+	return Request[GetCurrentSelfMenuInfoResponse](c).
+		Path(currentSelfMenuInfoPath).
+		Get(ctx)
+}
+
+// DeleteMenu 删除自定义菜单，同时删除全部个性化菜单
+func (c *Client) DeleteMenu(ctx context.Context) error {
+	_, err := Request[struct{}](c).
+		Path(menuDeletePath).
+		Get(ctx)
+	return err
+}
+
+// AddConditionalMenu 创建个性化菜单，发送前校验菜单结构与匹配规则
+func (c *Client) AddConditionalMenu(ctx context.Context, req AddConditionalMenuRequest) (AddConditionalMenuResponse, error) {
+	if err := validateMenuButtons(req.Buttons); err != nil {
+		return AddConditionalMenuResponse{}, err
+	}
+	if req.MatchRule == (MenuMatchRule{}) {
+		return AddConditionalMenuResponse{}, fmt.Errorf("matchrule is required")
+	}
+
+	return Request[AddConditionalMenuResponse](c).
+		Path(menuAddConditionalPath).
+		Body(req).
+		Post(ctx)
+}
+
+// DeleteConditionalMenu 删除个性化菜单
+func (c *Client) DeleteConditionalMenu(ctx context.Context, req DeleteConditionalMenuRequest) error {
+	if req.MenuID == "" {
+		return fmt.Errorf("menuid is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(menuDelConditionalPath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// TryMatchMenu 测试个性化菜单匹配结果
+func (c *Client) TryMatchMenu(ctx context.Context, req TryMatchMenuRequest) (TryMatchMenuResponse, error) {
+	if req.UserID == "" {
+		return TryMatchMenuResponse{}, fmt.Errorf("user_id is required")
+	}
+
+	return Request[TryMatchMenuResponse](c).
+		Path(menuTryMatchPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// validateMenuButtons 校验微信对菜单结构的限制：
+// 一级菜单 1~3 个、名称不超过 16 字节；二级菜单 1~5 个、名称不超过 60 字节。
+func validateMenuButtons(buttons []Button) error {
+	if len(buttons) == 0 {
+		return fmt.Errorf("menu requires at least one button")
+	}
+	if len(buttons) > maxMenuButtons {
+		return fmt.Errorf("menu allows at most %d buttons, got %d", maxMenuButtons, len(buttons))
+	}
+
+	for i, btn := range buttons {
+		path := fmt.Sprintf("button[%d]", i)
+		if err := validateButtonName(path, btn.Name, maxMenuButtonNameBytes); err != nil {
+			return err
+		}
+		if btn.SubButtons == nil {
+			if err := validateButtonAction(path, btn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if btn.Type != "" {
+			return fmt.Errorf("%s: button with sub_button must not set type", path)
+		}
+		if len(btn.SubButtons) == 0 || len(btn.SubButtons) > maxMenuSubButtons {
+			return fmt.Errorf("%s: sub_button requires 1 to %d buttons, got %d", path, maxMenuSubButtons, len(btn.SubButtons))
+		}
+		for j, sub := range btn.SubButtons {
+			subPath := fmt.Sprintf("%s.sub_button[%d]", path, j)
+			if err := validateButtonName(subPath, sub.Name, maxMenuSubButtonNameBytes); err != nil {
+				return err
+			}
+			if len(sub.SubButtons) > 0 {
+				return fmt.Errorf("%s: menu supports at most two levels", subPath)
+			}
+			if err := validateButtonAction(subPath, sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateButtonName(path, name string, maxBytes int) error {
+	if name == "" {
+		return fmt.Errorf("%s: name is required", path)
+	}
+	if len(name) > maxBytes {
+		return fmt.Errorf("%s: name exceeds %d bytes", path, maxBytes)
+	}
+	return nil
+}
+
+func validateButtonAction(path string, btn Button) error {
+	switch btn.Type {
+	case ButtonTypeClick, ButtonTypeScanCodePush, ButtonTypeScanCodeWaitMsg,
+		ButtonTypePicSysPhoto, ButtonTypePicPhotoOrAlbum, ButtonTypePicWeixin, ButtonTypeLocationSelect:
+		if btn.Key == "" {
+			return fmt.Errorf("%s: key is required for %s button", path, btn.Type)
+		}
+		if len(btn.Key) > maxMenuButtonKeyBytes {
+			return fmt.Errorf("%s: key exceeds %d bytes", path, maxMenuButtonKeyBytes)
+		}
+	case ButtonTypeView:
+		if btn.URL == "" {
+			return fmt.Errorf("%s: url is required for view button", path)
+		}
+	case ButtonTypeMiniprogram:
+		// url 为不支持小程序的旧版客户端打开的网页
+		if btn.URL == "" || btn.AppID == "" || btn.PagePath == "" {
+			return fmt.Errorf("%s: url, appid and pagepath are required for miniprogram button", path)
+		}
+	case ButtonTypeMediaID, ButtonTypeViewLimited:
+		if btn.MediaID == "" {
+			return fmt.Errorf("%s: media_id is required for %s button", path, btn.Type)
+		}
+	case ButtonTypeArticleID, ButtonTypeArticleViewLimited:
+		if btn.ArticleID == "" {
+			return fmt.Errorf("%s: article_id is required for %s button", path, btn.Type)
+		}
+	case "":
+		return fmt.Errorf("%s: type is required", path)
+	default:
+		return fmt.Errorf("%s: unsupported button type %q", path, btn.Type)
+	}
+
+	if len(btn.URL) > maxMenuButtonURLBytes {
+		return fmt.Errorf("%s: url exceeds %d bytes", path, maxMenuButtonURLBytes)
+	}
+	return nil
+}
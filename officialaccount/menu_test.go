@@ -0,0 +1,107 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestValidateMenuButtons(t *testing.T) {
+	click := Button{Type: ButtonTypeClick, Name: "今日歌曲", Key: "V1001_TODAY_MUSIC"}
+	tests := []struct {
+		name    string
+		buttons []Button
+		wantErr string
+	}{
+		{name: "valid", buttons: []Button{click, {Name: "菜单", SubButtons: []Button{
+			{Type: ButtonTypeView, Name: "搜索", URL: "https://www.soso.com/"},
+			{Type: ButtonTypeMiniprogram, Name: "小程序", URL: "https://example.com", AppID: "wx1", PagePath: "pages/index"},
+		}}}},
+		{name: "empty", wantErr: "at least one button"},
+		{name: "too many top-level", buttons: []Button{click, click, click, click}, wantErr: "at most 3 buttons"},
+		{name: "top-level name too long", buttons: []Button{{Type: ButtonTypeClick, Name: "一二三四五六", Key: "k"}}, wantErr: "button[0]: name exceeds 16 bytes"},
+		{name: "too many sub buttons", buttons: []Button{{Name: "菜单", SubButtons: []Button{click, click, click, click, click, click}}}, wantErr: "1 to 5 buttons"},
+		{name: "missing key", buttons: []Button{{Type: ButtonTypeScanCodePush, Name: "扫码"}}, wantErr: "key is required"},
+		{name: "missing pagepath", buttons: []Button{{Type: ButtonTypeMiniprogram, Name: "小程序", URL: "u", AppID: "wx1"}}, wantErr: "pagepath"},
+		{name: "missing article id", buttons: []Button{{Name: "菜单", SubButtons: []Button{{Type: ButtonTypeArticleID, Name: "文章"}}}}, wantErr: "button[0].sub_button[0]: article_id is required"},
+		{name: "container with type", buttons: []Button{{Type: ButtonTypeClick, Name: "菜单", SubButtons: []Button{click}}}, wantErr: "must not set type"},
+		{name: "unknown type", buttons: []Button{{Type: "unknown", Name: "x"}}, wantErr: "unsupported button type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMenuButtons(tt.buttons)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMenuAPIs(t *testing.T) {
+	var created map[string]any
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case menuCreatePath:
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case menuAddConditionalPath:
+			var req AddConditionalMenuRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.MatchRule.TagID != "2" {
+				t.Errorf("unexpected matchrule: %+v", req.MatchRule)
+			}
+			_, _ = w.Write([]byte(`{"menuid":"208379533"}`))
+		case currentSelfMenuInfoPath:
+			_, _ = w.Write([]byte(`{"is_menu_open":1,"selfmenu_info":{"button":[{"name":"菜单","sub_button":{"list":[` +
+				`{"type":"news","name":"图文","value":"KQb","news_info":{"list":[{"title":"t1","content_url":"u1"}]}}]}}]}}`))
+		case menuDelConditionalPath:
+			_, _ = w.Write([]byte(`{"errcode":65301,"errmsg":"no such menuid"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	err := client.CreateMenu(ctx, CreateMenuRequest{Buttons: []Button{{Type: ButtonTypeClick, Name: "歌曲", Key: "MUSIC"}}})
+	if err != nil {
+		t.Fatalf("create menu: %v", err)
+	}
+	buttons, _ := created["button"].([]any)
+	if len(buttons) != 1 || buttons[0].(map[string]any)["key"] != "MUSIC" {
+		t.Fatalf("unexpected create payload: %v", created)
+	}
+
+	if err := client.CreateMenu(ctx, CreateMenuRequest{}); err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	added, err := client.AddConditionalMenu(ctx, AddConditionalMenuRequest{
+		Buttons:   []Button{{Type: ButtonTypeView, Name: "官网", URL: "https://example.com"}},
+		MatchRule: MenuMatchRule{TagID: "2"},
+	})
+	if err != nil || added.MenuID != "208379533" {
+		t.Fatalf("add conditional menu: %+v %v", added, err)
+	}
+
+	info, err := client.GetCurrentSelfMenuInfo(ctx)
+	if err != nil {
+		t.Fatalf("get current selfmenu info: %v", err)
+	}
+	sub := info.SelfMenuInfo.Buttons[0].SubButton
+	if info.IsMenuOpen != 1 || sub == nil || sub.List[0].NewsInfo.List[0].Title != "t1" {
+		t.Fatalf("unexpected selfmenu info: %+v", info)
+	}
+
+	if err := client.DeleteConditionalMenu(ctx, DeleteConditionalMenuRequest{MenuID: "1"}); err == nil {
+		t.Fatal("expected wechat error")
+	}
+}
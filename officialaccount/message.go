@@ -0,0 +1,163 @@
+package officialaccount
+
+import "encoding/xml"
+
+// MsgType 消息类型
+type MsgType string
+
+const (
+	MsgTypeText       MsgType = "text"
+	MsgTypeImage      MsgType = "image"
+	MsgTypeVoice      MsgType = "voice"
+	MsgTypeVideo      MsgType = "video"
+	MsgTypeShortVideo MsgType = "shortvideo"
+	MsgTypeLocation   MsgType = "location"
+	MsgTypeLink       MsgType = "link"
+	MsgTypeMusic      MsgType = "music"
+	MsgTypeNews       MsgType = "news"
+	MsgTypeEvent      MsgType = "event"
+)
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventSubscribe             EventType = "subscribe"
+	EventUnsubscribe           EventType = "unsubscribe"
+	EventScan                  EventType = "SCAN"
+	EventLocation              EventType = "LOCATION"
+	EventClick                 EventType = "CLICK"
+	EventView                  EventType = "VIEW"
+	EventViewMiniprogram       EventType = "view_miniprogram"
+	EventScanCodePush          EventType = "scancode_push"
+	EventScanCodeWaitMsg       EventType = "scancode_waitmsg"
+	EventPicSysPhoto           EventType = "pic_sysphoto"
+	EventPicPhotoOrAlbum       EventType = "pic_photo_or_album"
+	EventPicWeixin             EventType = "pic_weixin"
+	EventLocationSelect        EventType = "location_select"
+	EventTemplateSendJobFinish EventType = "TEMPLATESENDJOBFINISH"
+	EventMassSendJobFinish     EventType = "MASSSENDJOBFINISH"
+)
+
+// Message 公众号推送的普通消息与事件
+// 不同 MsgType / Event 使用的字段不同，未使用的字段为零值。
+type Message struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName"`
+	FromUserName string   `xml:"FromUserName"`
+	CreateTime   int64    `xml:"CreateTime"`
+	MsgType      MsgType  `xml:"MsgType"`
+	MsgID        int64    `xml:"MsgId"`
+	MsgDataID    string   `xml:"MsgDataId"`
+	Idx          string   `xml:"Idx"`
+
+	// 文本消息
+	Content string `xml:"Content"`
+
+	// 图片、语音、视频消息
+	PicURL       string `xml:"PicUrl"`
+	MediaID      string `xml:"MediaId"`
+	Format       string `xml:"Format"`
+	Recognition  string `xml:"Recognition"`
+	ThumbMediaID string `xml:"ThumbMediaId"`
+
+	// 地理位置消息
+	LocationX float64 `xml:"Location_X"`
+	LocationY float64 `xml:"Location_Y"`
+	Scale     int     `xml:"Scale"`
+	Label     string  `xml:"Label"`
+
+	// 链接消息
+	Title       string `xml:"Title"`
+	Description string `xml:"Description"`
+	URL         string `xml:"Url"`
+
+	// 事件
+	Event     EventType `xml:"Event"`
+	EventKey  string    `xml:"EventKey"`
+	Ticket    string    `xml:"Ticket"`
+	Latitude  float64   `xml:"Latitude"`
+	Longitude float64   `xml:"Longitude"`
+	Precision float64   `xml:"Precision"`
+	MenuID    string    `xml:"MenuId"`
+
+	// 自定义菜单扫码、发图、发送位置事件
+	ScanCodeInfo     *ScanCodeInfo     `xml:"ScanCodeInfo"`
+	SendPicsInfo     *SendPicsInfo     `xml:"SendPicsInfo"`
+	SendLocationInfo *SendLocationInfo `xml:"SendLocationInfo"`
+
+	// 模板消息与群发结果事件（TEMPLATESENDJOBFINISH、MASSSENDJOBFINISH），注意与普通消息的 MsgId 大小写不同
+	EventMsgID int64  `xml:"MsgID"`
+	Status     string `xml:"Status"`
+
+	// 群发结果事件（MASSSENDJOBFINISH）
+	TotalCount           int                   `xml:"TotalCount"`
+	FilterCount          int                   `xml:"FilterCount"`
+	SentCount            int                   `xml:"SentCount"`
+	ErrorCount           int                   `xml:"ErrorCount"`
+	CopyrightCheckResult *CopyrightCheckResult `xml:"CopyrightCheckResult"`
+	ArticleURLResult     *ArticleURLResult     `xml:"ArticleUrlResult"`
+}
+
+// IsEvent 是否为事件推送
+func (m *Message) IsEvent() bool {
+	return m.MsgType == MsgTypeEvent
+}
+
+// ScanCodeInfo 扫码事件信息
+type ScanCodeInfo struct {
+	ScanType   string `xml:"ScanType"`
+	ScanResult string `xml:"ScanResult"`
+}
+
+// SendPicsInfo 发图事件信息
+type SendPicsInfo struct {
+	Count   int           `xml:"Count"`
+	PicList []SendPicItem `xml:"PicList>item"`
+}
+
+// SendPicItem 发图事件中的单张图片
+type SendPicItem struct {
+	PicMD5Sum string `xml:"PicMd5Sum"`
+}
+
+// SendLocationInfo 发送位置事件信息
+type SendLocationInfo struct {
+	LocationX float64 `xml:"Location_X"`
+	LocationY float64 `xml:"Location_Y"`
+	Scale     int     `xml:"Scale"`
+	Label     string  `xml:"Label"`
+	PoiName   string  `xml:"Poiname"`
+}
+
+// CopyrightCheckResult 群发图文的原创校验结果
+type CopyrightCheckResult struct {
+	Count      int                  `xml:"Count"`
+	ResultList []CopyrightCheckItem `xml:"ResultList>item"`
+	// CheckState 整体校验结果，1 未被判为转载可群发，2 被判为转载可群发，3 被判为转载不能群发
+	CheckState int `xml:"CheckState"`
+}
+
+// CopyrightCheckItem 单篇文章的原创校验结果
+type CopyrightCheckItem struct {
+	ArticleIdx            int    `xml:"ArticleIdx"`
+	UserDeclareState      int    `xml:"UserDeclareState"`
+	AuditState            int    `xml:"AuditState"`
+	OriginalArticleURL    string `xml:"OriginalArticleUrl"`
+	OriginalArticleType   int    `xml:"OriginalArticleType"`
+	CanReprint            int    `xml:"CanReprint"`
+	NeedReplaceContent    int    `xml:"NeedReplaceContent"`
+	NeedShowReprintSource int    `xml:"NeedShowReprintSource"`
+}
+
+// ArticleURLResult 群发图文的文章链接
+type ArticleURLResult struct {
+	Count      int              `xml:"Count"`
+	ResultList []ArticleURLItem `xml:"ResultList>item"`
+}
+
+// ArticleURLItem 单篇文章的链接
+type ArticleURLItem struct {
+	ArticleIdx int    `xml:"ArticleIdx"`
+	ArticleURL string `xml:"ArticleUrl"`
+}
@@ -0,0 +1,292 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+const (
+	oauthAuthorizeURL       = "https://open.weixin.qq.com/connect/oauth2/authorize"
+	oauthAccessTokenPath    = "/sns/oauth2/access_token"
+	oauthRefreshTokenPath   = "/sns/oauth2/refresh_token"
+	oauthUserInfoPath       = "/sns/userinfo"
+	oauthAuthPath           = "/sns/auth"
+	userTokenCacheKeyPrefix = "officialaccount:oauth_token:"
+	userTokenExpireBuffer   = 60
+	userRefreshTokenTTL     = 30 * 24 * time.Hour
+	maxOAuthStateLength     = 128
+)
+
+var oauthStatePattern = regexp.MustCompile(`^[a-zA-Z0-9]*$`)
+
+// OAuthScope 网页授权作用域
+type OAuthScope string
+
+const (
+	// OAuthScopeBase 静默授权，只能获取 openid
+	OAuthScopeBase OAuthScope = "snsapi_base"
+	// OAuthScopeUserInfo 弹出授权页，可获取昵称、头像等信息
+	OAuthScopeUserInfo OAuthScope = "snsapi_userinfo"
+)
+
+// ErrUserTokenNotFound 用户 token 不在存储中或 refresh_token 已过期，需要重新授权
+var ErrUserTokenNotFound = errors.New("oauth user token not found")
+
+type AuthorizeURLRequest struct {
+	// RedirectURI 授权后重定向的回调地址，域名需与网页授权域名一致
+	RedirectURI string
+	Scope       OAuthScope
+	// State 重定向后原样带回，只能包含 a-zA-Z0-9，最多 128 字节
+	State string
+	// ForcePopup 为 true 时强制弹出授权页（snsapi_userinfo 下用户曾经授权过也会弹出）
+	ForcePopup bool
+}
+
+type ExchangeOAuthCodeRequest struct {
+	Code string
+}
+
+type RefreshOAuthTokenRequest struct {
+	RefreshToken string
+}
+
+// OAuthAccessToken 网页授权 access_token，与公众号全局 access_token 不同，每个用户单独获取
+type OAuthAccessToken struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	OpenID       string `json:"openid"`
+	Scope        string `json:"scope"`
+	// IsSnapshotUser 为 1 时是快照页模式虚拟账号，此时 openid 不代表真实用户
+	IsSnapshotUser int    `json:"is_snapshotuser"`
+	UnionID        string `json:"unionid"`
+}
+
+type GetOAuthUserInfoRequest struct {
+	AccessToken string
+	OpenID      string
+	// Lang 国家地区语言版本：zh_CN、zh_TW、en，默认为 zh_CN
+	Lang string
+}
+
+type OAuthUserInfo struct {
+	OpenID     string   `json:"openid"`
+	Nickname   string   `json:"nickname"`
+	Sex        int      `json:"sex"`
+	Province   string   `json:"province"`
+	City       string   `json:"city"`
+	Country    string   `json:"country"`
+	HeadImgURL string   `json:"headimgurl"`
+	Privilege  []string `json:"privilege"`
+	UnionID    string   `json:"unionid"`
+}
+
+type CheckOAuthTokenRequest struct {
+	AccessToken string
+	OpenID      string
+}
+
+// AuthorizeURL 生成网页授权链接，用户在微信内打开后重定向到 RedirectURI?code=CODE&state=STATE
+func (c *Client) AuthorizeURL(req AuthorizeURLRequest) (string, error) {
+	if req.RedirectURI == "" {
+		return "", fmt.Errorf("redirect_uri is required")
+	}
+	scope := req.Scope
+	if scope == "" {
+		scope = OAuthScopeBase
+	}
+	if scope != OAuthScopeBase && scope != OAuthScopeUserInfo {
+		return "", fmt.Errorf("invalid scope: %s", scope)
+	}
+	if len(req.State) > maxOAuthStateLength || !oauthStatePattern.MatchString(req.State) {
+		return "", fmt.Errorf("state must be at most %d characters of a-zA-Z0-9", maxOAuthStateLength)
+	}
+
+	// 微信要求参数按固定顺序排列，不能使用 url.Values.Encode 的字典序
+	var b strings.Builder
+	b.WriteString(oauthAuthorizeURL)
+	b.WriteString("?appid=" + url.QueryEscape(c.cfg.AppID))
+	b.WriteString("&redirect_uri=" + url.QueryEscape(req.RedirectURI))
+	b.WriteString("&response_type=code")
+	b.WriteString("&scope=" + string(scope))
+	b.WriteString("&state=" + req.State)
+	if req.ForcePopup {
+		b.WriteString("&forcePopup=true")
+	}
+	b.WriteString("#wechat_redirect")
+	return b.String(), nil
+}
+
+// ExchangeOAuthCode 通过授权回调的 code 换取网页授权 access_token，code 只能使用一次，5 分钟未使用自动过期，
+// 因此即使配置了 Retry 也不会重试
+func (c *Client) ExchangeOAuthCode(ctx context.Context, req ExchangeOAuthCodeRequest) (OAuthAccessToken, error) {
+	if req.Code == "" {
+		return OAuthAccessToken{}, fmt.Errorf("code is required")
+	}
+
+	return Request[OAuthAccessToken](c).
+		Path(oauthAccessTokenPath).
+		Query("appid", c.cfg.AppID).
+		Query("secret", c.cfg.AppSecret).
+		Query("code", req.Code).
+		Query("grant_type", "authorization_code").
+		WithoutToken().
+		Idempotent(false).
+		Get(ctx)
+}
+
+// RefreshOAuthToken 使用 refresh_token 刷新网页授权 access_token，refresh_token 有效期为 30 天
+func (c *Client) RefreshOAuthToken(ctx context.Context, req RefreshOAuthTokenRequest) (OAuthAccessToken, error) {
+	if req.RefreshToken == "" {
+		return OAuthAccessToken{}, fmt.Errorf("refresh_token is required")
+	}
+
+	return Request[OAuthAccessToken](c).
+		Path(oauthRefreshTokenPath).
+		Query("appid", c.cfg.AppID).
+		Query("grant_type", "refresh_token").
+		Query("refresh_token", req.RefreshToken).
+		WithoutToken().
+		Get(ctx)
+}
+
+// GetOAuthUserInfo 拉取用户信息，需要 snsapi_userinfo 授权
+func (c *Client) GetOAuthUserInfo(ctx context.Context, req GetOAuthUserInfoRequest) (OAuthUserInfo, error) {
+	if req.AccessToken == "" || req.OpenID == "" {
+		return OAuthUserInfo{}, fmt.Errorf("access_token and openid are required")
+	}
+	lang := req.Lang
+	if lang == "" {
+		lang = "zh_CN"
+	}
+
+	return Request[OAuthUserInfo](c).
+		Path(oauthUserInfoPath).
+		Query("access_token", req.AccessToken).
+		Query("openid", req.OpenID).
+		Query("lang", lang).
+		WithoutToken().
+		Get(ctx)
+}
+
+// CheckOAuthToken 检验网页授权 access_token 是否有效
+func (c *Client) CheckOAuthToken(ctx context.Context, req CheckOAuthTokenRequest) error {
+	if req.AccessToken == "" || req.OpenID == "" {
+		return fmt.Errorf("access_token and openid are required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(oauthAuthPath).
+		Query("access_token", req.AccessToken).
+		Query("openid", req.OpenID).
+		WithoutToken().
+		Get(ctx)
+	return err
+}
+
+// UserTokenStore 按 openid 缓存网页授权 token，access_token 过期时自动用 refresh_token 刷新
+type UserTokenStore struct {
+	client *Client
+	cache  core.Cache
+}
+
+type cachedUserToken struct {
+	Token            OAuthAccessToken `json:"token"`
+	ExpiresAt        int64            `json:"expires_at"`
+	RefreshExpiresAt int64            `json:"refresh_expires_at"`
+}
+
+// NewUserTokenStore 创建用户 token 存储，cache 为 nil 时使用 Config.Cache
+func (c *Client) NewUserTokenStore(cache core.Cache) *UserTokenStore {
+	if cache == nil {
+		cache = c.cfg.Cache
+	}
+	return &UserTokenStore{client: c, cache: cache}
+}
+
+// Save 保存换取或刷新得到的 token，有效期按 refresh_token 的 30 天计算
+func (s *UserTokenStore) Save(ctx context.Context, token OAuthAccessToken) error {
+	if token.OpenID == "" || token.AccessToken == "" {
+		return fmt.Errorf("openid and access_token are required")
+	}
+
+	now := time.Now()
+	// 有效期不足缓冲时间时视为已过期，下次读取直接走 refresh_token
+	cached := cachedUserToken{
+		Token:            token,
+		ExpiresAt:        now.Add(time.Duration(token.ExpiresIn-userTokenExpireBuffer) * time.Second).Unix(),
+		RefreshExpiresAt: now.Add(userRefreshTokenTTL).Unix(),
+	}
+	if old, ok := s.load(ctx, token.OpenID); ok && old.Token.RefreshToken == token.RefreshToken {
+		// 刷新 access_token 不会延长 refresh_token 的有效期
+		cached.RefreshExpiresAt = old.RefreshExpiresAt
+	}
+
+	value, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("marshal user token: %w", err)
+	}
+	ttl := time.Until(time.Unix(cached.RefreshExpiresAt, 0))
+	if err := s.cache.Set(ctx, s.cacheKey(token.OpenID), string(value), ttl); err != nil {
+		return fmt.Errorf("cache user token: %w", err)
+	}
+	return nil
+}
+
+// Token 返回有效的用户 token，access_token 即将过期时自动刷新并保存。
+// 没有缓存或 refresh_token 失效时返回 ErrUserTokenNotFound，需要引导用户重新授权。
+func (s *UserTokenStore) Token(ctx context.Context, openID string) (OAuthAccessToken, error) {
+	cached, ok := s.load(ctx, openID)
+	if !ok {
+		return OAuthAccessToken{}, ErrUserTokenNotFound
+	}
+	if time.Now().Unix() < cached.ExpiresAt {
+		return cached.Token, nil
+	}
+
+	token, err := s.client.RefreshOAuthToken(ctx, RefreshOAuthTokenRequest{RefreshToken: cached.Token.RefreshToken})
+	if err != nil {
+		var we *core.WechatError
+		if errors.As(err, &we) && (we.ErrCode == core.ErrCodeInvalidRefreshToken || we.ErrCode == core.ErrCodeExpiredRefreshToken) {
+			// refresh_token 失效，清除后要求重新授权；其他错误（如系统繁忙、频率限制）保留 token 供稍后重试
+			if delErr := s.Delete(ctx, openID); delErr != nil {
+				s.client.cfg.Logger.WarnContext(ctx, "delete user token failed", "openid", openID, "error", delErr)
+			}
+			return OAuthAccessToken{}, fmt.Errorf("%w: refresh failed: %w", ErrUserTokenNotFound, err)
+		}
+		return OAuthAccessToken{}, fmt.Errorf("refresh user token: %w", err)
+	}
+	if err := s.Save(ctx, token); err != nil {
+		s.client.cfg.Logger.WarnContext(ctx, "save refreshed user token failed", "openid", openID, "error", err)
+	}
+	return token, nil
+}
+
+// Delete 删除用户 token
+func (s *UserTokenStore) Delete(ctx context.Context, openID string) error {
+	return s.cache.Delete(ctx, s.cacheKey(openID))
+}
+
+func (s *UserTokenStore) load(ctx context.Context, openID string) (cachedUserToken, bool) {
+	raw, ok := s.cache.Get(ctx, s.cacheKey(openID))
+	if !ok {
+		return cachedUserToken{}, false
+	}
+	var cached cachedUserToken
+	if err := json.Unmarshal([]byte(raw), &cached); err != nil || cached.Token.AccessToken == "" {
+		return cachedUserToken{}, false
+	}
+	return cached, true
+}
+
+func (s *UserTokenStore) cacheKey(openID string) string {
+	return userTokenCacheKeyPrefix + s.client.cfg.AppID + ":" + openID
+}
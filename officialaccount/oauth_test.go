@@ -0,0 +1,204 @@
+package officialaccount
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+func TestAuthorizeURL(t *testing.T) {
+	client, err := New(Config{AppID: "wx520c15f417810387", AppSecret: "secret"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	got, err := client.AuthorizeURL(AuthorizeURLRequest{
+		RedirectURI: "https://example.com/callback?a=1",
+		Scope:       OAuthScopeUserInfo,
+		State:       "abc123",
+		ForcePopup:  true,
+	})
+	if err != nil {
+		t.Fatalf("authorize url: %v", err)
+	}
+	want := "https://open.weixin.qq.com/connect/oauth2/authorize?appid=wx520c15f417810387" +
+		"&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback%3Fa%3D1&response_type=code" +
+		"&scope=snsapi_userinfo&state=abc123&forcePopup=true#wechat_redirect"
+	if got != want {
+		t.Fatalf("unexpected url:\n got %s\nwant %s", got, want)
+	}
+
+	got, err = client.AuthorizeURL(AuthorizeURLRequest{RedirectURI: "https://example.com"})
+	if err != nil || !strings.Contains(got, "scope=snsapi_base&state=#wechat_redirect") {
+		t.Fatalf("unexpected default url: %s %v", got, err)
+	}
+
+	if _, err := client.AuthorizeURL(AuthorizeURLRequest{RedirectURI: "https://example.com", State: "a-b"}); err == nil {
+		t.Fatal("expected state validation error")
+	}
+}
+
+func TestOAuthFlow(t *testing.T) {
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case oauthAccessTokenPath:
+			if q.Get("code") != "code-1" || q.Get("secret") != "secret" || q.Get("grant_type") != "authorization_code" {
+				t.Errorf("unexpected exchange query: %v", q)
+			}
+			// expires_in 小于缓冲时间，保存后立即视为过期
+			_, _ = w.Write([]byte(`{"access_token":"user-token-1","expires_in":30,"refresh_token":"refresh-1","openid":"openid-1","scope":"snsapi_userinfo"}`))
+		case oauthRefreshTokenPath:
+			atomic.AddInt32(&refreshes, 1)
+			if q.Get("refresh_token") != "refresh-1" {
+				_, _ = w.Write([]byte(`{"errcode":40030,"errmsg":"invalid refresh_token"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"user-token-2","expires_in":7200,"refresh_token":"refresh-1","openid":"openid-1","scope":"snsapi_userinfo"}`))
+		case oauthUserInfoPath:
+			if q.Get("access_token") != "user-token-2" || q.Get("lang") != "en" {
+				t.Errorf("unexpected userinfo query: %v", q)
+			}
+			_, _ = w.Write([]byte(`{"openid":"openid-1","nickname":"Band","sex":1,"privilege":["PRIVILEGE1"],"unionid":"union-1"}`))
+		case oauthAuthPath:
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	store := client.NewUserTokenStore(nil)
+
+	if _, err := store.Token(ctx, "openid-1"); !errors.Is(err, ErrUserTokenNotFound) {
+		t.Fatalf("expected ErrUserTokenNotFound, got %v", err)
+	}
+
+	token, err := client.ExchangeOAuthCode(ctx, ExchangeOAuthCodeRequest{Code: "code-1"})
+	if err != nil || token.OpenID != "openid-1" {
+		t.Fatalf("exchange code: %+v %v", token, err)
+	}
+	if err := store.Save(ctx, token); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	token, err = store.Token(ctx, "openid-1")
+	if err != nil || token.AccessToken != "user-token-2" {
+		t.Fatalf("expected refreshed token, got %+v %v", token, err)
+	}
+	token, err = store.Token(ctx, "openid-1")
+	if err != nil || token.AccessToken != "user-token-2" || atomic.LoadInt32(&refreshes) != 1 {
+		t.Fatalf("expected cached token without refresh, got %+v %v after %d refreshes", token, err, refreshes)
+	}
+
+	info, err := client.GetOAuthUserInfo(ctx, GetOAuthUserInfoRequest{AccessToken: token.AccessToken, OpenID: token.OpenID, Lang: "en"})
+	if err != nil || info.Nickname != "Band" || info.UnionID != "union-1" {
+		t.Fatalf("get userinfo: %+v %v", info, err)
+	}
+	if err := client.CheckOAuthToken(ctx, CheckOAuthTokenRequest{AccessToken: token.AccessToken, OpenID: token.OpenID}); err != nil {
+		t.Fatalf("check token: %v", err)
+	}
+}
+
+func TestUserTokenStoreRefreshTokenExpired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":42002,"errmsg":"refresh_token expired"}`))
+	}))
+	defer server.Close()
+
+	cache := core.NewMemoryCache()
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL, Cache: cache})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	store := client.NewUserTokenStore(nil)
+
+	if err := store.Save(ctx, OAuthAccessToken{AccessToken: "t", ExpiresIn: 1, RefreshToken: "r", OpenID: "openid-1"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	_, err = store.Token(ctx, "openid-1")
+	var we *core.WechatError
+	if !errors.Is(err, ErrUserTokenNotFound) || !errors.As(err, &we) || we.ErrCode != 42002 {
+		t.Fatalf("expected ErrUserTokenNotFound wrapping 42002, got %v", err)
+	}
+	if _, ok := cache.Get(ctx, "officialaccount:oauth_token:appid:openid-1"); ok {
+		t.Fatal("expired user token should be deleted")
+	}
+}
+
+func TestUserTokenStoreKeepsTokenOnTransientError(t *testing.T) {
+	var busy atomic.Bool
+	busy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if busy.Load() {
+			_, _ = w.Write([]byte(`{"errcode":45011,"errmsg":"api minute-quota reach limit"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"t2","expires_in":7200,"refresh_token":"r","openid":"openid-1"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	store := client.NewUserTokenStore(nil)
+	if err := store.Save(ctx, OAuthAccessToken{AccessToken: "t", ExpiresIn: 1, RefreshToken: "r", OpenID: "openid-1"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	_, err = store.Token(ctx, "openid-1")
+	var we *core.WechatError
+	if err == nil || errors.Is(err, ErrUserTokenNotFound) || !errors.As(err, &we) || we.ErrCode != core.ErrCodeFreqLimit {
+		t.Fatalf("expected wrapped 45011 without ErrUserTokenNotFound, got %v", err)
+	}
+
+	busy.Store(false)
+	token, err := store.Token(ctx, "openid-1")
+	if err != nil || token.AccessToken != "t2" {
+		t.Fatalf("token should be kept and refreshed later: %+v %v", token, err)
+	}
+}
+
+func TestExchangeOAuthCodeNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"errcode":-1,"errmsg":"system error"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(Config{
+		AppID:     "appid",
+		AppSecret: "secret",
+		BaseURL:   server.URL,
+		Retry:     core.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	_, err = client.ExchangeOAuthCode(context.Background(), ExchangeOAuthCodeRequest{Code: "code-1"})
+	var we *core.WechatError
+	if !errors.As(err, &we) || we.ErrCode != core.ErrCodeBusy {
+		t.Fatalf("expected -1 error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("oauth code must be sent once, got %d calls", got)
+	}
+}
@@ -0,0 +1,238 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+const (
+	qrcodeCreatePath        = "/cgi-bin/qrcode/create"
+	showQRCodePath          = "/cgi-bin/showqrcode"
+	shortenGenPath          = "/cgi-bin/shorten/gen"
+	shortenFetchPath        = "/cgi-bin/shorten/fetch"
+	qrSceneEventKeyPrefix   = "qrscene_"
+	maxQRCodeExpireSeconds  = 2592000
+	maxQRLimitSceneID       = 100000
+	maxQRSceneStrLength     = 64
+	maxShortenExpireSeconds = 2592000
+	maxShortenLongDataBytes = 4096
+)
+
+// QRCodeAction 二维码类型
+type QRCodeAction string
+
+const (
+	// QRCodeActionScene 临时整型参数二维码
+	QRCodeActionScene QRCodeAction = "QR_SCENE"
+	// QRCodeActionStrScene 临时字符串参数二维码
+	QRCodeActionStrScene QRCodeAction = "QR_STR_SCENE"
+	// QRCodeActionLimitScene 永久整型参数二维码，场景值 1~100000
+	QRCodeActionLimitScene QRCodeAction = "QR_LIMIT_SCENE"
+	// QRCodeActionLimitStrScene 永久字符串参数二维码
+	QRCodeActionLimitStrScene QRCodeAction = "QR_LIMIT_STR_SCENE"
+)
+
+// IsTemporary 是否为临时二维码
+func (a QRCodeAction) IsTemporary() bool {
+	return a == QRCodeActionScene || a == QRCodeActionStrScene
+}
+
+type CreateQRCodeRequest struct {
+	Action QRCodeAction
+	// ExpireSeconds 临时二维码有效期，不填默认 30 秒，最大 2592000（30 天）；永久二维码忽略
+	ExpireSeconds int
+	// SceneID 整型场景值，用于 QR_SCENE / QR_LIMIT_SCENE
+	SceneID int64
+	// SceneStr 字符串场景值，长度 1~64，用于 QR_STR_SCENE / QR_LIMIT_STR_SCENE
+	SceneStr string
+}
+
+type CreateQRCodeResponse struct {
+	Ticket        string `json:"ticket"`
+	ExpireSeconds int    `json:"expire_seconds"`
+	// URL 二维码图片解析后的地址，可自行生成二维码图片
+	URL string `json:"url"`
+}
+
+type GenShortenRequest struct {
+	// LongData 需要转换的长信息，不超过 4KB
+	LongData string `json:"long_data"`
+	// ExpireSeconds 过期秒数，不填默认 2592000（30 天）
+	ExpireSeconds int `json:"expire_seconds,omitempty"`
+}
+
+type GenShortenResponse struct {
+	ShortKey string `json:"short_key"`
+}
+
+type FetchShortenRequest struct {
+	ShortKey string `json:"short_key"`
+}
+
+type FetchShortenResponse struct {
+	LongData      string `json:"long_data"`
+	CreateTime    int64  `json:"create_time"`
+	ExpireSeconds int    `json:"expire_seconds"`
+}
+
+type qrcodeScene struct {
+	SceneID  int64  `json:"scene_id,omitempty"`
+	SceneStr string `json:"scene_str,omitempty"`
+}
+
+type qrcodeActionInfo struct {
+	Scene qrcodeScene `json:"scene"`
+}
+
+type createQRCodeBody struct {
+	ExpireSeconds int              `json:"expire_seconds,omitempty"`
+	ActionName    QRCodeAction     `json:"action_name"`
+	ActionInfo    qrcodeActionInfo `json:"action_info"`
+}
+
+// CreateQRCode 生成带参数的二维码，返回用于换取二维码图片的 ticket
+func (c *Client) CreateQRCode(ctx context.Context, req CreateQRCodeRequest) (CreateQRCodeResponse, error) {
+	if err := validateCreateQRCode(req); err != nil {
+		return CreateQRCodeResponse{}, err
+	}
+
+	body := createQRCodeBody{ActionName: req.Action}
+	if req.Action.IsTemporary() {
+		body.ExpireSeconds = req.ExpireSeconds
+	}
+	switch req.Action {
+	case QRCodeActionScene, QRCodeActionLimitScene:
+		body.ActionInfo.Scene.SceneID = req.SceneID
+	default:
+		body.ActionInfo.Scene.SceneStr = req.SceneStr
+	}
+
+	return Request[CreateQRCodeResponse](c).
+		Path(qrcodeCreatePath).
+		Body(body).
+		Post(ctx)
+}
+
+// QRCodeURL 返回通过 ticket 换取二维码图片的地址，无需 access_token，可直接用于展示
+func QRCodeURL(ticket string) string {
+	return defaultMPBaseURL + showQRCodePath + "?ticket=" + url.QueryEscape(ticket)
+}
+
+// DownloadQRCode 通过 ticket 下载二维码图片，请求发往 Config.MPBaseURL，调用方负责关闭返回的 Body
+func (c *Client) DownloadQRCode(ctx context.Context, ticket string) (*core.StreamResponse, error) {
+	if ticket == "" {
+		return nil, fmt.Errorf("ticket is required")
+	}
+
+	return c.mpClient.Request().
+		Path(showQRCodePath).
+		Query("ticket", ticket).
+		WithoutToken().
+		Idempotent(true).
+		GetStream(ctx)
+}
+
+// GenShorten 将长信息转换为短 key，可用于二维码场景值等长度受限的场合
+func (c *Client) GenShorten(ctx context.Context, req GenShortenRequest) (GenShortenResponse, error) {
+	if req.LongData == "" {
+		return GenShortenResponse{}, fmt.Errorf("long_data is required")
+	}
+	if len(req.LongData) > maxShortenLongDataBytes {
+		return GenShortenResponse{}, fmt.Errorf("long_data exceeds %d bytes", maxShortenLongDataBytes)
+	}
+	if req.ExpireSeconds < 0 || req.ExpireSeconds > maxShortenExpireSeconds {
+		return GenShortenResponse{}, fmt.Errorf("expire_seconds must be between 0 and %d", maxShortenExpireSeconds)
+	}
+
+	return Request[GenShortenResponse](c).
+		Path(shortenGenPath).
+		Body(req).
+		Post(ctx)
+}
+
+// FetchShorten 通过短 key 还原长信息
+func (c *Client) FetchShorten(ctx context.Context, req FetchShortenRequest) (FetchShortenResponse, error) {
+	if req.ShortKey == "" {
+		return FetchShortenResponse{}, fmt.Errorf("short_key is required")
+	}
+
+	return Request[FetchShortenResponse](c).
+		Path(shortenFetchPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// QRScene 扫描带参数二维码事件携带的场景值
+type QRScene string
+
+// ID 按整型场景值解析，字符串场景值返回 false
+func (s QRScene) ID() (int64, bool) {
+	id, err := strconv.ParseInt(string(s), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// ParseQRScene 从事件的 EventKey 中解析二维码场景值。
+// 未关注用户扫码关注（subscribe）时 EventKey 为 qrscene_ 前缀加场景值，已关注用户扫码（SCAN）时为场景值本身；
+// 普通关注事件或其他事件返回 false。
+func ParseQRScene(event EventType, eventKey string) (QRScene, bool) {
+	switch event {
+	case EventSubscribe:
+		scene, ok := strings.CutPrefix(eventKey, qrSceneEventKeyPrefix)
+		if !ok || scene == "" {
+			return "", false
+		}
+		return QRScene(scene), true
+	case EventScan:
+		if eventKey == "" {
+			return "", false
+		}
+		return QRScene(eventKey), true
+	default:
+		return "", false
+	}
+}
+
+// QRScene 返回扫描带参数二维码事件的场景值，见 ParseQRScene
+func (m *Message) QRScene() (QRScene, bool) {
+	if !m.IsEvent() {
+		return "", false
+	}
+	return ParseQRScene(m.Event, m.EventKey)
+}
+
+func validateCreateQRCode(req CreateQRCodeRequest) error {
+	switch req.Action {
+	case QRCodeActionScene, QRCodeActionLimitScene:
+		if req.SceneID <= 0 {
+			return fmt.Errorf("scene_id must be positive")
+		}
+		if req.Action == QRCodeActionLimitScene && req.SceneID > maxQRLimitSceneID {
+			return fmt.Errorf("scene_id exceeds %d for %s", maxQRLimitSceneID, req.Action)
+		}
+		if req.Action == QRCodeActionScene && req.SceneID > 1<<32-1 {
+			return fmt.Errorf("scene_id exceeds 32-bit range")
+		}
+	case QRCodeActionStrScene, QRCodeActionLimitStrScene:
+		if req.SceneStr == "" {
+			return fmt.Errorf("scene_str is required")
+		}
+		if len(req.SceneStr) > maxQRSceneStrLength {
+			return fmt.Errorf("scene_str exceeds %d characters", maxQRSceneStrLength)
+		}
+	default:
+		return fmt.Errorf("invalid qrcode action: %q", req.Action)
+	}
+	if req.Action.IsTemporary() && (req.ExpireSeconds < 0 || req.ExpireSeconds > maxQRCodeExpireSeconds) {
+		return fmt.Errorf("expire_seconds must be between 0 and %d", maxQRCodeExpireSeconds)
+	}
+	return nil
+}
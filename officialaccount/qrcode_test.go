@@ -0,0 +1,191 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+func TestCreateQRCode(t *testing.T) {
+	var got []map[string]any
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != qrcodeCreatePath {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, body)
+		_, _ = w.Write([]byte(`{"ticket":"gQH47joAAAAAAAAAASxod==","expire_seconds":60,"url":"http://weixin.qq.com/q/kZgfwMTm72WWPkovabbI"}`))
+	})
+	ctx := context.Background()
+
+	resp, err := client.CreateQRCode(ctx, CreateQRCodeRequest{Action: QRCodeActionScene, SceneID: 123, ExpireSeconds: 60})
+	if err != nil || resp.Ticket != "gQH47joAAAAAAAAAASxod==" || resp.ExpireSeconds != 60 {
+		t.Fatalf("create qrcode: %+v %v", resp, err)
+	}
+	if _, err := client.CreateQRCode(ctx, CreateQRCodeRequest{Action: QRCodeActionLimitStrScene, SceneStr: "channel_a", ExpireSeconds: 60}); err != nil {
+		t.Fatalf("create limit qrcode: %v", err)
+	}
+
+	if got[0]["action_name"] != "QR_SCENE" || got[0]["expire_seconds"] != float64(60) ||
+		got[0]["action_info"].(map[string]any)["scene"].(map[string]any)["scene_id"] != float64(123) {
+		t.Fatalf("unexpected temporary body: %v", got[0])
+	}
+	if _, ok := got[1]["expire_seconds"]; ok {
+		t.Fatalf("permanent qrcode should omit expire_seconds: %v", got[1])
+	}
+	if got[1]["action_info"].(map[string]any)["scene"].(map[string]any)["scene_str"] != "channel_a" {
+		t.Fatalf("unexpected permanent body: %v", got[1])
+	}
+
+	invalid := []CreateQRCodeRequest{
+		{Action: QRCodeActionScene},
+		{Action: QRCodeActionLimitScene, SceneID: maxQRLimitSceneID + 1},
+		{Action: QRCodeActionStrScene, SceneStr: "a", ExpireSeconds: maxQRCodeExpireSeconds + 1},
+		{Action: "QR_UNKNOWN", SceneID: 1},
+	}
+	for _, req := range invalid {
+		if _, err := client.CreateQRCode(ctx, req); err == nil {
+			t.Fatalf("expected validation error for %+v", req)
+		}
+	}
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestDownloadQRCode(t *testing.T) {
+	if got := QRCodeURL("a+b/c=="); got != "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=a%2Bb%2Fc%3D%3D" {
+		t.Fatalf("unexpected qrcode url: %s", got)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cgi-bin/showqrcode" || r.URL.Query().Get("ticket") != "a+b/c==" || r.URL.Query().Has("access_token") {
+			t.Errorf("unexpected download request: %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("\xff\xd8jpeg"))
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", HTTPClient: &http.Client{Transport: rewriteTransport{target: target}}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.DownloadQRCode(context.Background(), "a+b/c==")
+	if err != nil {
+		t.Fatalf("download qrcode: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.ContentType != "image/jpeg" || string(data) != "\xff\xd8jpeg" {
+		t.Fatalf("unexpected image: %s %q", resp.ContentType, data)
+	}
+}
+
+func TestDownloadQRCodeUsesMPBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != showQRCodePath || r.URL.Query().Get("ticket") != "ticket-1" {
+			t.Errorf("unexpected download request: %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("\xff\xd8jpeg"))
+	}))
+	defer server.Close()
+
+	var paths []string
+	recorder := core.MiddlewareFuncs{
+		Request: func(ctx context.Context, req *core.MiddlewareRequest) (context.Context, error) {
+			paths = append(paths, req.Path)
+			return ctx, nil
+		},
+	}
+	client, err := New(Config{AppID: "appid", AppSecret: "secret", MPBaseURL: server.URL, Middlewares: []core.Middleware{recorder}})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	resp, err := client.DownloadQRCode(context.Background(), "ticket-1")
+	if err != nil {
+		t.Fatalf("download qrcode: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(paths) != 1 || paths[0] != showQRCodePath {
+		t.Fatalf("middleware should see api path, got %v", paths)
+	}
+}
+
+func TestShorten(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case shortenGenPath:
+			var req GenShortenRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.LongData != "loooooong data" || req.ExpireSeconds != 86400 {
+				t.Errorf("unexpected gen request: %+v", req)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","short_key":"iTVj2v"}`))
+		case shortenFetchPath:
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","long_data":"loooooong data","create_time":1611047541,"expire_seconds":86400}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	gen, err := client.GenShorten(ctx, GenShortenRequest{LongData: "loooooong data", ExpireSeconds: 86400})
+	if err != nil || gen.ShortKey != "iTVj2v" {
+		t.Fatalf("gen shorten: %+v %v", gen, err)
+	}
+	fetched, err := client.FetchShorten(ctx, FetchShortenRequest{ShortKey: gen.ShortKey})
+	if err != nil || fetched.LongData != "loooooong data" || fetched.CreateTime != 1611047541 {
+		t.Fatalf("fetch shorten: %+v %v", fetched, err)
+	}
+}
+
+func TestParseQRScene(t *testing.T) {
+	cases := []struct {
+		event EventType
+		key   string
+		scene QRScene
+		ok    bool
+		id    int64
+		hasID bool
+	}{
+		{EventSubscribe, "qrscene_123", "123", true, 123, true},
+		{EventSubscribe, "qrscene_channel_a", "channel_a", true, 0, false},
+		{EventSubscribe, "", "", false, 0, false},
+		{EventScan, "123", "123", true, 123, true},
+		{EventScan, "channel_a", "channel_a", true, 0, false},
+		{EventClick, "qrscene_1", "", false, 0, false},
+	}
+	for _, tc := range cases {
+		scene, ok := ParseQRScene(tc.event, tc.key)
+		if scene != tc.scene || ok != tc.ok {
+			t.Fatalf("%s %q: got %q %v", tc.event, tc.key, scene, ok)
+		}
+		if id, hasID := scene.ID(); id != tc.id || hasID != tc.hasID {
+			t.Fatalf("%s %q: got id %d %v", tc.event, tc.key, id, hasID)
+		}
+	}
+
+	msg := &Message{MsgType: MsgTypeEvent, Event: EventSubscribe, EventKey: "qrscene_42", Ticket: "t"}
+	if scene, ok := msg.QRScene(); !ok || scene != "42" {
+		t.Fatalf("unexpected message scene: %q %v", scene, ok)
+	}
+}
@@ -0,0 +1,27 @@
+package officialaccount
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+// NewRefresher 创建后台刷新器，注册 access_token 与 ticket 的续期任务。
+// tickets 为空时同时续期 jsapi 与 wx_card ticket。返回的刷新器需调用 Start 启动、Stop 停止。
+func (c *Client) NewRefresher(cfg core.RefresherConfig, tickets ...TicketType) *core.Refresher {
+	if cfg.Logger == nil {
+		cfg.Logger = c.cfg.Logger
+	}
+	if len(tickets) == 0 {
+		tickets = []TicketType{TicketTypeJSAPI, TicketTypeWxCard}
+	}
+
+	refresher := core.NewRefresher(cfg).Add("access_token", c.tokenManager.Renew)
+	for _, ticketType := range tickets {
+		refresher.Add(string(ticketType)+"_ticket", func(ctx context.Context, ahead time.Duration) (time.Duration, error) {
+			return c.renewTicket(ctx, ticketType, ahead)
+		})
+	}
+	return refresher
+}
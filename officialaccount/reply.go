@@ -0,0 +1,173 @@
+package officialaccount
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Reply 被动回复消息
+// 由 TextReply、ImageReply、VoiceReply、VideoReply、MusicReply、NewsReply 实现。
+type Reply interface {
+	apply(env *replyEnvelope)
+}
+
+// TextReply 回复文本消息
+type TextReply struct {
+	Content string
+}
+
+// ImageReply 回复图片消息
+type ImageReply struct {
+	MediaID string
+}
+
+// VoiceReply 回复语音消息
+type VoiceReply struct {
+	MediaID string
+}
+
+// VideoReply 回复视频消息
+type VideoReply struct {
+	MediaID     string
+	Title       string
+	Description string
+}
+
+// MusicReply 回复音乐消息
+type MusicReply struct {
+	Title        string
+	Description  string
+	MusicURL     string
+	HQMusicURL   string
+	ThumbMediaID string
+}
+
+// NewsReply 回复图文消息，微信限制最多 1 条图文
+type NewsReply struct {
+	Articles []NewsArticle
+}
+
+// NewsArticle 图文消息中的单条图文
+type NewsArticle struct {
+	Title       string
+	Description string
+	PicURL      string
+	URL         string
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+type replyMedia struct {
+	MediaID cdata `xml:"MediaId"`
+}
+
+type replyVideo struct {
+	MediaID     cdata `xml:"MediaId"`
+	Title       cdata `xml:"Title"`
+	Description cdata `xml:"Description"`
+}
+
+type replyMusic struct {
+	Title        cdata `xml:"Title"`
+	Description  cdata `xml:"Description"`
+	MusicURL     cdata `xml:"MusicUrl"`
+	HQMusicURL   cdata `xml:"HQMusicUrl"`
+	ThumbMediaID cdata `xml:"ThumbMediaId"`
+}
+
+type replyArticle struct {
+	Title       cdata `xml:"Title"`
+	Description cdata `xml:"Description"`
+	PicURL      cdata `xml:"PicUrl"`
+	URL         cdata `xml:"Url"`
+}
+
+type replyEnvelope struct {
+	XMLName      xml.Name       `xml:"xml"`
+	ToUserName   cdata          `xml:"ToUserName"`
+	FromUserName cdata          `xml:"FromUserName"`
+	CreateTime   int64          `xml:"CreateTime"`
+	MsgType      cdata          `xml:"MsgType"`
+	Content      *cdata         `xml:"Content,omitempty"`
+	Image        *replyMedia    `xml:"Image,omitempty"`
+	Voice        *replyMedia    `xml:"Voice,omitempty"`
+	Video        *replyVideo    `xml:"Video,omitempty"`
+	Music        *replyMusic    `xml:"Music,omitempty"`
+	ArticleCount int            `xml:"ArticleCount,omitempty"`
+	Articles     []replyArticle `xml:"Articles>item,omitempty"`
+}
+
+func (r TextReply) apply(env *replyEnvelope) {
+	env.MsgType = cdata{string(MsgTypeText)}
+	env.Content = &cdata{r.Content}
+}
+
+func (r ImageReply) apply(env *replyEnvelope) {
+	env.MsgType = cdata{string(MsgTypeImage)}
+	env.Image = &replyMedia{MediaID: cdata{r.MediaID}}
+}
+
+func (r VoiceReply) apply(env *replyEnvelope) {
+	env.MsgType = cdata{string(MsgTypeVoice)}
+	env.Voice = &replyMedia{MediaID: cdata{r.MediaID}}
+}
+
+func (r VideoReply) apply(env *replyEnvelope) {
+	env.MsgType = cdata{string(MsgTypeVideo)}
+	env.Video = &replyVideo{
+		MediaID:     cdata{r.MediaID},
+		Title:       cdata{r.Title},
+		Description: cdata{r.Description},
+	}
+}
+
+func (r MusicReply) apply(env *replyEnvelope) {
+	env.MsgType = cdata{string(MsgTypeMusic)}
+	env.Music = &replyMusic{
+		Title:        cdata{r.Title},
+		Description:  cdata{r.Description},
+		MusicURL:     cdata{r.MusicURL},
+		HQMusicURL:   cdata{r.HQMusicURL},
+		ThumbMediaID: cdata{r.ThumbMediaID},
+	}
+}
+
+func (r NewsReply) apply(env *replyEnvelope) {
+	env.MsgType = cdata{string(MsgTypeNews)}
+	env.ArticleCount = len(r.Articles)
+	env.Articles = make([]replyArticle, 0, len(r.Articles))
+	for _, a := range r.Articles {
+		env.Articles = append(env.Articles, replyArticle{
+			Title:       cdata{a.Title},
+			Description: cdata{a.Description},
+			PicURL:      cdata{a.PicURL},
+			URL:         cdata{a.URL},
+		})
+	}
+}
+
+// MarshalReply 将被动回复序列化为 XML，收发方与 msg 相反
+func MarshalReply(msg *Message, reply Reply) ([]byte, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("message is required")
+	}
+	if reply == nil {
+		return nil, fmt.Errorf("reply is required")
+	}
+
+	env := &replyEnvelope{
+		ToUserName:   cdata{msg.FromUserName},
+		FromUserName: cdata{msg.ToUserName},
+		CreateTime:   time.Now().Unix(),
+	}
+	reply.apply(env)
+
+	out, err := xml.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("marshal reply: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package officialaccount
+
+import "context"
+
+// MessageHandler 消息处理函数，返回 nil Reply 表示不回复
+type MessageHandler func(ctx context.Context, msg *Message) (Reply, error)
+
+type routeKey struct {
+	msgType  MsgType
+	event    EventType
+	eventKey string
+}
+
+// Router 按 MsgType / Event / EventKey 分发消息
+// 匹配优先级：Event+EventKey > Event > MsgType > Default。
+type Router struct {
+	routes   map[routeKey]MessageHandler
+	fallback MessageHandler
+}
+
+// NewRouter 创建消息路由
+func NewRouter() *Router {
+	return &Router{routes: make(map[routeKey]MessageHandler)}
+}
+
+// Message 注册普通消息处理函数
+func (r *Router) Message(msgType MsgType, h MessageHandler) *Router {
+	r.routes[routeKey{msgType: msgType}] = h
+	return r
+}
+
+// Event 注册事件处理函数
+func (r *Router) Event(event EventType, h MessageHandler) *Router {
+	r.routes[routeKey{msgType: MsgTypeEvent, event: event}] = h
+	return r
+}
+
+// EventKey 注册指定 EventKey 的事件处理函数，如菜单 CLICK 的 key
+func (r *Router) EventKey(event EventType, key string, h MessageHandler) *Router {
+	r.routes[routeKey{msgType: MsgTypeEvent, event: event, eventKey: key}] = h
+	return r
+}
+
+// Default 注册兜底处理函数
+func (r *Router) Default(h MessageHandler) *Router {
+	r.fallback = h
+	return r
+}
+
+// Dispatch 分发消息，没有匹配的处理函数时返回 nil Reply
+func (r *Router) Dispatch(ctx context.Context, msg *Message) (Reply, error) {
+	if h := r.match(msg); h != nil {
+		return h(ctx, msg)
+	}
+	return nil, nil
+}
+
+func (r *Router) match(msg *Message) MessageHandler {
+	if msg.IsEvent() {
+		if h, ok := r.routes[routeKey{msgType: MsgTypeEvent, event: msg.Event, eventKey: msg.EventKey}]; ok && msg.EventKey != "" {
+			return h
+		}
+		if h, ok := r.routes[routeKey{msgType: MsgTypeEvent, event: msg.Event}]; ok {
+			return h
+		}
+	}
+	if h, ok := r.routes[routeKey{msgType: msg.MsgType}]; ok {
+		return h
+	}
+	return r.fallback
+}
@@ -0,0 +1,157 @@
+package officialaccount
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ShinyNito/FunkWechat/v2/core/utils"
+)
+
+const (
+	defaultMaxCallbackBodySize = 1 << 20
+	callbackSuccess            = "success"
+)
+
+// ServerConfig 消息回调服务配置
+type ServerConfig struct {
+	// Token 公众号后台「服务器配置」中填写的 Token
+	Token string
+	// Router 消息路由
+	Router *Router
+	// EncodingAESKey 消息加解密密钥，配置后支持安全模式与兼容模式（encrypt_type=aes）
+	EncodingAESKey string
+	// MaxBodySize 请求体大小上限，<= 0 时使用默认值 1MB
+	MaxBodySize int64
+}
+
+// Server 公众号消息回调服务，实现 http.Handler
+// GET 请求用于服务器地址校验（原样返回 echostr），POST 请求解析消息、分发到 Router 并写回被动回复。
+type Server struct {
+	token       string
+	router      *Router
+	crypto      *utils.MsgCrypto
+	maxBodySize int64
+	logger      *slog.Logger
+}
+
+// NewServer 创建消息回调服务
+func (c *Client) NewServer(cfg ServerConfig) (*Server, error) {
+	if strings.TrimSpace(cfg.Token) == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+	if cfg.Router == nil {
+		return nil, fmt.Errorf("router is required")
+	}
+
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxCallbackBodySize
+	}
+
+	var crypto *utils.MsgCrypto
+	if cfg.EncodingAESKey != "" {
+		var err error
+		crypto, err = utils.NewMsgCrypto(cfg.Token, cfg.EncodingAESKey, c.cfg.AppID)
+		if err != nil {
+			return nil, fmt.Errorf("init message crypto: %w", err)
+		}
+	}
+
+	return &Server{
+		token:       cfg.Token,
+		router:      cfg.Router,
+		crypto:      crypto,
+		maxBodySize: maxBodySize,
+		logger:      c.cfg.Logger,
+	}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !utils.VerifySignature(query.Get("signature"), query.Get("timestamp"), query.Get("nonce"), s.token) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_, _ = io.WriteString(w, query.Get("echostr"))
+	case http.MethodPost:
+		s.handleMessage(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, s.maxBodySize+1))
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > s.maxBodySize {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	query := r.URL.Query()
+	encrypted := query.Get("encrypt_type") == "aes"
+	if encrypted {
+		if s.crypto == nil {
+			http.Error(w, "encoding aes key not configured", http.StatusBadRequest)
+			return
+		}
+		body, err = s.crypto.DecryptXML(query.Get("msg_signature"), query.Get("timestamp"), query.Get("nonce"), body)
+		if err != nil {
+			s.logger.WarnContext(ctx, "decrypt message failed", slog.Any("error", err))
+			http.Error(w, "decrypt message failed", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var msg Message
+	if err := xml.Unmarshal(body, &msg); err != nil {
+		http.Error(w, "invalid xml", http.StatusBadRequest)
+		return
+	}
+
+	reply, err := s.router.Dispatch(ctx, &msg)
+	if err != nil {
+		// 回复 success 避免微信重试并向用户提示「该公众号暂时无法提供服务」
+		s.logger.ErrorContext(ctx, "handle message failed",
+			slog.String("msg_type", string(msg.MsgType)),
+			slog.String("event", string(msg.Event)),
+			slog.Any("error", err),
+		)
+		_, _ = io.WriteString(w, callbackSuccess)
+		return
+	}
+	if reply == nil {
+		_, _ = io.WriteString(w, callbackSuccess)
+		return
+	}
+
+	out, err := MarshalReply(&msg, reply)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "marshal reply failed", slog.Any("error", err))
+		_, _ = io.WriteString(w, callbackSuccess)
+		return
+	}
+	if encrypted {
+		out, err = s.crypto.EncryptXML(out, query.Get("timestamp"), query.Get("nonce"))
+		if err != nil {
+			s.logger.ErrorContext(ctx, "encrypt reply failed", slog.Any("error", err))
+			_, _ = io.WriteString(w, callbackSuccess)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	_, _ = w.Write(out)
+}
@@ -0,0 +1,240 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ShinyNito/FunkWechat/v2/core/utils"
+)
+
+const testCallbackToken = "callback-token"
+
+func newTestServer(t *testing.T, router *Router) *Server {
+	t.Helper()
+	client, err := New(Config{AppID: "appid", AppSecret: "secret"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	server, err := client.NewServer(ServerConfig{Token: testCallbackToken, Router: router})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	return server
+}
+
+func signedQuery(extra url.Values) url.Values {
+	q := url.Values{}
+	q.Set("timestamp", "1700000000")
+	q.Set("nonce", "nonce")
+	q.Set("signature", utils.SHA1Sign(testCallbackToken, "1700000000", "nonce"))
+	for k, v := range extra {
+		q[k] = v
+	}
+	return q
+}
+
+func serveCallback(server *Server, method string, query url.Values, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/wechat?"+query.Encode(), strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerVerifyURL(t *testing.T) {
+	server := newTestServer(t, NewRouter())
+
+	rec := serveCallback(server, http.MethodGet, signedQuery(url.Values{"echostr": {"echo-1"}}), "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "echo-1" {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	query := signedQuery(url.Values{"echostr": {"echo-1"}})
+	query.Set("signature", "bad")
+	rec = serveCallback(server, http.MethodGet, query, "")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestServerTextReply(t *testing.T) {
+	router := NewRouter().Message(MsgTypeText, func(ctx context.Context, msg *Message) (Reply, error) {
+		return TextReply{Content: "echo: " + msg.Content}, nil
+	})
+	server := newTestServer(t, router)
+
+	body := `<xml><ToUserName><![CDATA[gh_1]]></ToUserName><FromUserName><![CDATA[openid-1]]></FromUserName>` +
+		`<CreateTime>1700000000</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hi <b>]]></Content><MsgId>42</MsgId></xml>`
+	rec := serveCallback(server, http.MethodPost, signedQuery(nil), body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", rec.Code)
+	}
+
+	var reply struct {
+		ToUserName   string `xml:"ToUserName"`
+		FromUserName string `xml:"FromUserName"`
+		MsgType      string `xml:"MsgType"`
+		Content      string `xml:"Content"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("unmarshal reply: %v", err)
+	}
+	if reply.ToUserName != "openid-1" || reply.FromUserName != "gh_1" || reply.MsgType != "text" || reply.Content != "echo: hi <b>" {
+		t.Fatalf("unexpected reply: %+v", reply)
+	}
+	if !strings.Contains(rec.Body.String(), "<![CDATA[echo: hi <b>]]>") {
+		t.Fatalf("reply should use CDATA: %s", rec.Body.String())
+	}
+}
+
+func TestServerEventRouting(t *testing.T) {
+	var got []string
+	record := func(name string) MessageHandler {
+		return func(ctx context.Context, msg *Message) (Reply, error) {
+			got = append(got, name)
+			return nil, nil
+		}
+	}
+	router := NewRouter().
+		Event(EventClick, record("click")).
+		EventKey(EventClick, "MENU_A", record("click:MENU_A")).
+		Event(EventSubscribe, record("subscribe")).
+		Message(MsgTypeEvent, record("any-event")).
+		Default(record("default"))
+	server := newTestServer(t, router)
+
+	events := []string{
+		`<xml><MsgType>event</MsgType><Event>CLICK</Event><EventKey>MENU_A</EventKey></xml>`,
+		`<xml><MsgType>event</MsgType><Event>CLICK</Event><EventKey>MENU_B</EventKey></xml>`,
+		`<xml><MsgType>event</MsgType><Event>subscribe</Event><EventKey>qrscene_1</EventKey><Ticket>t</Ticket></xml>`,
+		`<xml><MsgType>event</MsgType><Event>VIEW</Event><EventKey>https://example.com</EventKey></xml>`,
+		`<xml><MsgType>image</MsgType><PicUrl>https://example.com/a.jpg</PicUrl></xml>`,
+	}
+	for _, body := range events {
+		rec := serveCallback(server, http.MethodPost, signedQuery(nil), body)
+		if rec.Body.String() != callbackSuccess {
+			t.Fatalf("expected success, got %s", rec.Body.String())
+		}
+	}
+
+	want := []string{"click:MENU_A", "click", "subscribe", "any-event", "default"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected dispatch: %v", got)
+	}
+}
+
+func TestServerHandlerError(t *testing.T) {
+	router := NewRouter().Default(func(ctx context.Context, msg *Message) (Reply, error) {
+		return nil, errors.New("boom")
+	})
+	server := newTestServer(t, router)
+
+	rec := serveCallback(server, http.MethodPost, signedQuery(nil), `<xml><MsgType>text</MsgType></xml>`)
+	if rec.Code != http.StatusOK || rec.Body.String() != callbackSuccess {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveCallback(server, http.MethodPost, signedQuery(nil), `not xml`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestParseMessageEvents(t *testing.T) {
+	body := `<xml><ToUserName>gh</ToUserName><FromUserName>o1</FromUserName><CreateTime>1</CreateTime>
+<MsgType>event</MsgType><Event>pic_sysphoto</Event><EventKey>PHOTO</EventKey>
+<SendPicsInfo><Count>2</Count><PicList><item><PicMd5Sum>a</PicMd5Sum></item><item><PicMd5Sum>b</PicMd5Sum></item></PicList></SendPicsInfo>
+<MsgID>1001</MsgID><Status>success</Status></xml>`
+	var msg Message
+	if err := xml.Unmarshal([]byte(body), &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !msg.IsEvent() || msg.Event != EventPicSysPhoto {
+		t.Fatalf("unexpected event: %+v", msg)
+	}
+	if msg.SendPicsInfo == nil || msg.SendPicsInfo.Count != 2 || msg.SendPicsInfo.PicList[1].PicMD5Sum != "b" {
+		t.Fatalf("unexpected pics info: %+v", msg.SendPicsInfo)
+	}
+	if msg.EventMsgID != 1001 || msg.Status != "success" {
+		t.Fatalf("unexpected template finish fields: %d %s", msg.EventMsgID, msg.Status)
+	}
+}
+
+func TestMarshalReplyNews(t *testing.T) {
+	out, err := MarshalReply(&Message{ToUserName: "gh", FromUserName: "o1"}, NewsReply{Articles: []NewsArticle{
+		{Title: "t1", Description: "d1", PicURL: "p1", URL: "u1"},
+	}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{"<ArticleCount>1</ArticleCount>", "<Articles><item><Title><![CDATA[t1]]></Title>", "<MsgType><![CDATA[news]]></MsgType>"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("missing %q in %s", want, s)
+		}
+	}
+}
+
+func TestServerSafeMode(t *testing.T) {
+	const aesKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	client, err := New(Config{AppID: "appid", AppSecret: "secret"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	router := NewRouter().Message(MsgTypeText, func(ctx context.Context, msg *Message) (Reply, error) {
+		return TextReply{Content: "echo: " + msg.Content}, nil
+	})
+	server, err := client.NewServer(ServerConfig{Token: testCallbackToken, Router: router, EncodingAESKey: aesKey})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	crypto, err := utils.NewMsgCrypto(testCallbackToken, aesKey, "appid")
+	if err != nil {
+		t.Fatalf("new crypto: %v", err)
+	}
+
+	plain := `<xml><ToUserName><![CDATA[gh_1]]></ToUserName><FromUserName><![CDATA[openid-1]]></FromUserName>` +
+		`<MsgType><![CDATA[text]]></MsgType><Content><![CDATA[hi]]></Content></xml>`
+	encrypted, err := crypto.Encrypt([]byte(plain))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	body := `<xml><ToUserName><![CDATA[gh_1]]></ToUserName><Encrypt><![CDATA[` + encrypted + `]]></Encrypt></xml>`
+	query := signedQuery(url.Values{
+		"encrypt_type":  {"aes"},
+		"msg_signature": {crypto.Signature("1700000000", "nonce", encrypted)},
+	})
+
+	rec := serveCallback(server, http.MethodPost, query, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d %s", rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Encrypt      string `xml:"Encrypt"`
+		MsgSignature string `xml:"MsgSignature"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshal envelope: %v", err)
+	}
+	if !crypto.VerifySignature(envelope.MsgSignature, "1700000000", "nonce", envelope.Encrypt) {
+		t.Fatalf("invalid reply signature")
+	}
+	out, err := crypto.Decrypt(envelope.Encrypt)
+	if err != nil {
+		t.Fatalf("decrypt reply: %v", err)
+	}
+	if !strings.Contains(string(out), "<![CDATA[echo: hi]]>") {
+		t.Fatalf("unexpected reply: %s", out)
+	}
+
+	query.Set("msg_signature", "bad")
+	rec = serveCallback(server, http.MethodPost, query, body)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
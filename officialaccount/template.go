@@ -0,0 +1,174 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	templateSendPath          = "/cgi-bin/message/template/send"
+	templateAddPath           = "/cgi-bin/template/api_add_template"
+	templateGetAllPrivatePath = "/cgi-bin/template/get_all_private_template"
+	templateDelPrivatePath    = "/cgi-bin/template/del_private_template"
+	templateGetIndustryPath   = "/cgi-bin/template/get_industry"
+	templateSetIndustryPath   = "/cgi-bin/template/api_set_industry"
+)
+
+// TemplateDataItem 模板消息中单个关键词的内容
+type TemplateDataItem struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+// TemplateData 模板消息数据，key 为模板中的关键词名称（如 thing1、time2）
+type TemplateData map[string]TemplateDataItem
+
+// NewTemplateData 创建模板消息数据
+func NewTemplateData() TemplateData {
+	return make(TemplateData)
+}
+
+// Set 设置关键词内容
+func (d TemplateData) Set(key, value string) TemplateData {
+	d[key] = TemplateDataItem{Value: value}
+	return d
+}
+
+// SetColor 设置关键词内容与字体颜色（如 #173177）
+func (d TemplateData) SetColor(key, value, color string) TemplateData {
+	d[key] = TemplateDataItem{Value: value, Color: color}
+	return d
+}
+
+// TemplateMiniprogram 模板消息跳转的小程序
+type TemplateMiniprogram struct {
+	AppID    string `json:"appid"`
+	PagePath string `json:"pagepath,omitempty"`
+}
+
+type SendTemplateMessageRequest struct {
+	ToUser     string `json:"touser"`
+	TemplateID string `json:"template_id"`
+	// URL 点击跳转的网页，与 Miniprogram 同时设置时优先跳转小程序
+	URL         string               `json:"url,omitempty"`
+	Miniprogram *TemplateMiniprogram `json:"miniprogram,omitempty"`
+	Data        TemplateData         `json:"data"`
+	// ClientMsgID 防重入 ID，同一 ID 的消息只会发送一次；设置后发送失败可安全重试
+	ClientMsgID string `json:"client_msg_id,omitempty"`
+}
+
+type SendTemplateMessageResponse struct {
+	MsgID int64 `json:"msgid"`
+}
+
+type AddTemplateRequest struct {
+	TemplateIDShort string   `json:"template_id_short"`
+	KeywordNameList []string `json:"keyword_name_list,omitempty"`
+}
+
+type AddTemplateResponse struct {
+	TemplateID string `json:"template_id"`
+}
+
+type PrivateTemplate struct {
+	TemplateID      string `json:"template_id"`
+	Title           string `json:"title"`
+	PrimaryIndustry string `json:"primary_industry"`
+	DeputyIndustry  string `json:"deputy_industry"`
+	Content         string `json:"content"`
+	Example         string `json:"example"`
+}
+
+type GetAllPrivateTemplateResponse struct {
+	TemplateList []PrivateTemplate `json:"template_list"`
+}
+
+type DeletePrivateTemplateRequest struct {
+	TemplateID string `json:"template_id"`
+}
+
+type Industry struct {
+	FirstClass  string `json:"first_class"`
+	SecondClass string `json:"second_class"`
+}
+
+type GetIndustryResponse struct {
+	PrimaryIndustry   Industry `json:"primary_industry"`
+	SecondaryIndustry Industry `json:"secondary_industry"`
+}
+
+type SetIndustryRequest struct {
+	IndustryID1 string `json:"industry_id1"`
+	IndustryID2 string `json:"industry_id2"`
+}
+
+// SendTemplateMessage 发送模板消息，返回消息 ID
+func (c *Client) SendTemplateMessage(ctx context.Context, req SendTemplateMessageRequest) (SendTemplateMessageResponse, error) {
+	if req.ToUser == "" {
+		return SendTemplateMessageResponse{}, fmt.Errorf("touser is required")
+	}
+	if req.TemplateID == "" {
+		return SendTemplateMessageResponse{}, fmt.Errorf("template_id is required")
+	}
+	if req.Miniprogram != nil && req.Miniprogram.AppID == "" {
+		return SendTemplateMessageResponse{}, fmt.Errorf("miniprogram appid is required")
+	}
+
+	return Request[SendTemplateMessageResponse](c).
+		Path(templateSendPath).
+		Body(req).
+		Idempotent(req.ClientMsgID != "").
+		Post(ctx)
+}
+
+// AddTemplate 从模板库选用模板到账号，返回模板 ID
+func (c *Client) AddTemplate(ctx context.Context, req AddTemplateRequest) (AddTemplateResponse, error) {
+	if req.TemplateIDShort == "" {
+		return AddTemplateResponse{}, fmt.Errorf("template_id_short is required")
+	}
+
+	return Request[AddTemplateResponse](c).
+		Path(templateAddPath).
+		Body(req).
+		Post(ctx)
+}
+
+// GetAllPrivateTemplate 获取账号下已添加的模板列表
+func (c *Client) GetAllPrivateTemplate(ctx context.Context) (GetAllPrivateTemplateResponse, error) {
+	return Request[GetAllPrivateTemplateResponse](c).
+		Path(templateGetAllPrivatePath).
+		Get(ctx)
+}
+
+// DeletePrivateTemplate 删除账号下的模板
+func (c *Client) DeletePrivateTemplate(ctx context.Context, req DeletePrivateTemplateRequest) error {
+	if req.TemplateID == "" {
+		return fmt.Errorf("template_id is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(templateDelPrivatePath).
+		Body(req).
+		Post(ctx)
+	return err
+}
+
+// GetIndustry 获取账号设置的所属行业
+func (c *Client) GetIndustry(ctx context.Context) (GetIndustryResponse, error) {
+	return Request[GetIndustryResponse](c).
+		Path(templateGetIndustryPath).
+		Get(ctx)
+}
+
+// SetIndustry 设置账号所属行业，每月可修改一次
+func (c *Client) SetIndustry(ctx context.Context, req SetIndustryRequest) error {
+	if req.IndustryID1 == "" || req.IndustryID2 == "" {
+		return fmt.Errorf("industry_id1 and industry_id2 are required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(templateSetIndustryPath).
+		Body(req).
+		Post(ctx)
+	return err
+}
@@ -0,0 +1,94 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestSendTemplateMessage(t *testing.T) {
+	var got map[string]any
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != templateSendPath {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","msgid":200228332}`))
+	})
+
+	resp, err := client.SendTemplateMessage(context.Background(), SendTemplateMessageRequest{
+		ToUser:      "openid-1",
+		TemplateID:  "tpl-1",
+		Miniprogram: &TemplateMiniprogram{AppID: "wx1", PagePath: "pages/index"},
+		Data:        NewTemplateData().Set("thing1", "订单已发货").SetColor("time2", "2026-01-01", "#173177"),
+		ClientMsgID: "order-1",
+	})
+	if err != nil {
+		t.Fatalf("send template message: %v", err)
+	}
+	if resp.MsgID != 200228332 {
+		t.Fatalf("unexpected msgid: %d", resp.MsgID)
+	}
+
+	data := got["data"].(map[string]any)
+	if data["thing1"].(map[string]any)["value"] != "订单已发货" || data["time2"].(map[string]any)["color"] != "#173177" {
+		t.Fatalf("unexpected data: %v", data)
+	}
+	if got["client_msg_id"] != "order-1" || got["miniprogram"].(map[string]any)["appid"] != "wx1" {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+	if _, ok := got["url"]; ok {
+		t.Fatalf("empty url should be omitted: %v", got)
+	}
+
+	if _, err := client.SendTemplateMessage(context.Background(), SendTemplateMessageRequest{ToUser: "openid-1"}); err == nil {
+		t.Fatal("expected template_id validation error")
+	}
+}
+
+func TestTemplateLibrary(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case templateAddPath:
+			var req AddTemplateRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.TemplateIDShort != "TM00015" || len(req.KeywordNameList) != 2 {
+				t.Errorf("unexpected add request: %+v", req)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok","template_id":"tpl-1"}`))
+		case templateGetAllPrivatePath:
+			_, _ = w.Write([]byte(`{"template_list":[{"template_id":"tpl-1","title":"领取奖金提醒","content":"{{result.DATA}}"}]}`))
+		case templateGetIndustryPath:
+			_, _ = w.Write([]byte(`{"primary_industry":{"first_class":"运输与仓储","second_class":"快递"},"secondary_industry":{"first_class":"IT科技","second_class":"互联网|电子商务"}}`))
+		case templateDelPrivatePath, templateSetIndustryPath:
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	added, err := client.AddTemplate(ctx, AddTemplateRequest{TemplateIDShort: "TM00015", KeywordNameList: []string{"商品名称", "购买时间"}})
+	if err != nil || added.TemplateID != "tpl-1" {
+		t.Fatalf("add template: %+v %v", added, err)
+	}
+
+	list, err := client.GetAllPrivateTemplate(ctx)
+	if err != nil || len(list.TemplateList) != 1 || list.TemplateList[0].Title != "领取奖金提醒" {
+		t.Fatalf("get all private template: %+v %v", list, err)
+	}
+
+	industry, err := client.GetIndustry(ctx)
+	if err != nil || industry.PrimaryIndustry.SecondClass != "快递" {
+		t.Fatalf("get industry: %+v %v", industry, err)
+	}
+
+	if err := client.DeletePrivateTemplate(ctx, DeletePrivateTemplateRequest{TemplateID: "tpl-1"}); err != nil {
+		t.Fatalf("delete private template: %v", err)
+	}
+	if err := client.SetIndustry(ctx, SetIndustryRequest{IndustryID1: "1", IndustryID2: "4"}); err != nil {
+		t.Fatalf("set industry: %v", err)
+	}
+}
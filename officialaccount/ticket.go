@@ -49,27 +49,59 @@ func (c *Client) GetTicket(ctx context.Context, req GetTicketRequest) (string, e
 			c.cfg.Logger.WarnContext(ctx, "delete invalid cached ticket failed", "type", ticketType, "error", err)
 		}
 	}
-	return c.refreshTicket(ctx, ticketType)
+	return c.refreshTicket(ctx, ticketType, false)
 }
 
 func (c *Client) RefreshTicket(ctx context.Context, ticketType TicketType) (string, error) {
 	if ticketType == "" {
 		ticketType = TicketTypeJSAPI
 	}
-	return c.refreshTicket(ctx, ticketType)
+	return c.refreshTicket(ctx, ticketType, false)
 }
 
-func (c *Client) refreshTicket(ctx context.Context, ticketType TicketType) (string, error) {
+// renewTicket 后台刷新任务：缓存中的 ticket 距过期不足 ahead 时重新获取，返回距下一次续期的等待时长。
+func (c *Client) renewTicket(ctx context.Context, ticketType TicketType, ahead time.Duration) (time.Duration, error) {
+	if next, ok := c.ticketRenewDelay(ctx, ticketType, ahead); ok {
+		return next, nil
+	}
+	if _, err := c.refreshTicket(ctx, ticketType, true); err != nil {
+		return 0, err
+	}
+	if next, ok := c.ticketRenewDelay(ctx, ticketType, ahead); ok {
+		return next, nil
+	}
+	return ahead, nil
+}
+
+func (c *Client) ticketRenewDelay(ctx context.Context, ticketType TicketType, ahead time.Duration) (time.Duration, bool) {
+	raw, ok := c.cfg.Cache.Get(ctx, c.ticketCacheKey(ticketType))
+	if !ok {
+		return 0, false
+	}
+	var cached cachedTicket
+	if err := json.Unmarshal([]byte(raw), &cached); err != nil || cached.Ticket == "" || cached.ExpiresAt <= 0 {
+		return 0, false
+	}
+	remaining := time.Until(time.Unix(cached.ExpiresAt, 0))
+	if remaining <= ahead {
+		return 0, false
+	}
+	return remaining - ahead, true
+}
+
+func (c *Client) refreshTicket(ctx context.Context, ticketType TicketType, force bool) (string, error) {
 	c.ticketMu.Lock()
 	defer c.ticketMu.Unlock()
 
 	cacheKey := c.ticketCacheKey(ticketType)
-	if raw, ok := c.cfg.Cache.Get(ctx, cacheKey); ok {
-		if ticket, ok := decodeCachedTicket(raw); ok {
-			return ticket, nil
-		}
-		if err := c.cfg.Cache.Delete(ctx, cacheKey); err != nil {
-			c.cfg.Logger.WarnContext(ctx, "delete invalid cached ticket failed", "type", ticketType, "error", err)
+	if !force {
+		if raw, ok := c.cfg.Cache.Get(ctx, cacheKey); ok {
+			if ticket, ok := decodeCachedTicket(raw); ok {
+				return ticket, nil
+			}
+			if err := c.cfg.Cache.Delete(ctx, cacheKey); err != nil {
+				c.cfg.Logger.WarnContext(ctx, "delete invalid cached ticket failed", "type", ticketType, "error", err)
+			}
 		}
 	}
 
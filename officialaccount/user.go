@@ -0,0 +1,420 @@
+package officialaccount
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"unicode/utf8"
+)
+
+const (
+	userInfoPath             = "/cgi-bin/user/info"
+	userInfoBatchGetPath     = "/cgi-bin/user/info/batchget"
+	userGetPath              = "/cgi-bin/user/get"
+	userUpdateRemarkPath     = "/cgi-bin/user/info/updateremark"
+	tagsCreatePath           = "/cgi-bin/tags/create"
+	tagsGetPath              = "/cgi-bin/tags/get"
+	tagsUpdatePath           = "/cgi-bin/tags/update"
+	tagsDeletePath           = "/cgi-bin/tags/delete"
+	tagUserGetPath           = "/cgi-bin/user/tag/get"
+	tagsBatchTaggingPath     = "/cgi-bin/tags/members/batchtagging"
+	tagsBatchUntaggingPath   = "/cgi-bin/tags/members/batchuntagging"
+	tagsGetIDListPath        = "/cgi-bin/tags/getidlist"
+	blacklistGetPath         = "/cgi-bin/tags/members/getblacklist"
+	blacklistBatchPath       = "/cgi-bin/tags/members/batchblacklist"
+	blacklistBatchUnsetPath  = "/cgi-bin/tags/members/batchunblacklist"
+	maxBatchGetUserInfoCount = 100
+	maxBatchTaggingCount     = 50
+	maxBatchBlacklistCount   = 20
+	maxUserRemarkRunes       = 30
+	maxTagNameRunes          = 30
+)
+
+// UserInfo 用户基本信息，未关注时只返回 Subscribe 与 OpenID
+type UserInfo struct {
+	Subscribe      int     `json:"subscribe"`
+	OpenID         string  `json:"openid"`
+	Language       string  `json:"language"`
+	SubscribeTime  int64   `json:"subscribe_time"`
+	UnionID        string  `json:"unionid"`
+	Remark         string  `json:"remark"`
+	GroupID        int64   `json:"groupid"`
+	TagIDList      []int64 `json:"tagid_list"`
+	SubscribeScene string  `json:"subscribe_scene"`
+	QRScene        int64   `json:"qr_scene"`
+	QRSceneStr     string  `json:"qr_scene_str"`
+}
+
+// OpenIDList 关注者、标签粉丝、黑名单列表的一页
+type OpenIDList struct {
+	Total int `json:"total"`
+	Count int `json:"count"`
+	Data  struct {
+		OpenID []string `json:"openid"`
+	} `json:"data"`
+	NextOpenID string `json:"next_openid"`
+}
+
+type GetUserInfoRequest struct {
+	OpenID string
+	// Lang 默认 zh_CN
+	Lang string
+}
+
+type BatchGetUserInfoItem struct {
+	OpenID string `json:"openid"`
+	Lang   string `json:"lang,omitempty"`
+}
+
+type BatchGetUserInfoRequest struct {
+	UserList []BatchGetUserInfoItem `json:"user_list"`
+}
+
+type BatchGetUserInfoResponse struct {
+	UserInfoList []UserInfo `json:"user_info_list"`
+}
+
+type GetUserListRequest struct {
+	// NextOpenID 为空时从头拉取
+	NextOpenID string
+}
+
+type UpdateUserRemarkRequest struct {
+	OpenID string `json:"openid"`
+	Remark string `json:"remark"`
+}
+
+// Tag 用户标签
+type Tag struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+type CreateTagRequest struct {
+	Name string
+}
+
+type GetTagsResponse struct {
+	Tags []Tag `json:"tags"`
+}
+
+type UpdateTagRequest struct {
+	ID   int64
+	Name string
+}
+
+type DeleteTagRequest struct {
+	ID int64
+}
+
+type GetTagUsersRequest struct {
+	TagID      int64  `json:"tagid"`
+	NextOpenID string `json:"next_openid,omitempty"`
+}
+
+type BatchTaggingRequest struct {
+	OpenIDList []string `json:"openid_list"`
+	TagID      int64    `json:"tagid"`
+}
+
+type GetUserTagIDsRequest struct {
+	OpenID string `json:"openid"`
+}
+
+type GetUserTagIDsResponse struct {
+	TagIDList []int64 `json:"tagid_list"`
+}
+
+type GetBlacklistRequest struct {
+	BeginOpenID string `json:"begin_openid"`
+}
+
+type BatchBlacklistRequest struct {
+	OpenIDList []string `json:"openid_list"`
+}
+
+type tagBody struct {
+	Tag Tag `json:"tag"`
+}
+
+// GetUserInfo 获取用户基本信息
+func (c *Client) GetUserInfo(ctx context.Context, req GetUserInfoRequest) (UserInfo, error) {
+	if req.OpenID == "" {
+		return UserInfo{}, fmt.Errorf("openid is required")
+	}
+	if req.Lang == "" {
+		req.Lang = "zh_CN"
+	}
+
+	return Request[UserInfo](c).
+		Path(userInfoPath).
+		Query("openid", req.OpenID).
+		Query("lang", req.Lang).
+		Get(ctx)
+}
+
+// BatchGetUserInfo 批量获取用户基本信息，每次最多 100 个
+func (c *Client) BatchGetUserInfo(ctx context.Context, req BatchGetUserInfoRequest) (BatchGetUserInfoResponse, error) {
+	if len(req.UserList) == 0 {
+		return BatchGetUserInfoResponse{}, fmt.Errorf("user_list is required")
+	}
+	if len(req.UserList) > maxBatchGetUserInfoCount {
+		return BatchGetUserInfoResponse{}, fmt.Errorf("user_list exceeds %d users", maxBatchGetUserInfoCount)
+	}
+
+	return Request[BatchGetUserInfoResponse](c).
+		Path(userInfoBatchGetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// GetUserList 获取关注者列表的一页，每页最多 10000 个 OpenID
+func (c *Client) GetUserList(ctx context.Context, req GetUserListRequest) (OpenIDList, error) {
+	r := Request[OpenIDList](c).Path(userGetPath)
+	if req.NextOpenID != "" {
+		r = r.Query("next_openid", req.NextOpenID)
+	}
+	return r.Get(ctx)
+}
+
+// Users 遍历全部关注者 OpenID，自动跟随 next_openid 翻页。
+// 请求失败时产出一次非 nil 错误并结束遍历。
+func (c *Client) Users(ctx context.Context) iter.Seq2[string, error] {
+	return iterateOpenIDs(func(next string) (OpenIDList, error) {
+		return c.GetUserList(ctx, GetUserListRequest{NextOpenID: next})
+	})
+}
+
+// UpdateUserRemark 设置用户备注名，长度不超过 30 个字符
+func (c *Client) UpdateUserRemark(ctx context.Context, req UpdateUserRemarkRequest) error {
+	if req.OpenID == "" {
+		return fmt.Errorf("openid is required")
+	}
+	if utf8.RuneCountInString(req.Remark) > maxUserRemarkRunes {
+		return fmt.Errorf("remark exceeds %d characters", maxUserRemarkRunes)
+	}
+
+	_, err := Request[struct{}](c).
+		Path(userUpdateRemarkPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// CreateTag 创建标签，返回标签 ID 与名称
+func (c *Client) CreateTag(ctx context.Context, req CreateTagRequest) (Tag, error) {
+	if err := validateTagName(req.Name); err != nil {
+		return Tag{}, err
+	}
+
+	resp, err := Request[tagBody](c).
+		Path(tagsCreatePath).
+		Body(tagBody{Tag: Tag{Name: req.Name}}).
+		Post(ctx)
+	if err != nil {
+		return Tag{}, err
+	}
+	return resp.Tag, nil
+}
+
+// GetTags 获取已创建的标签及各标签下的粉丝数
+func (c *Client) GetTags(ctx context.Context) (GetTagsResponse, error) {
+	return Request[GetTagsResponse](c).
+		Path(tagsGetPath).
+		Get(ctx)
+}
+
+// UpdateTag 修改标签名
+func (c *Client) UpdateTag(ctx context.Context, req UpdateTagRequest) error {
+	if req.ID == 0 {
+		return fmt.Errorf("tag id is required")
+	}
+	if err := validateTagName(req.Name); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(tagsUpdatePath).
+		Body(tagBody{Tag: Tag{ID: req.ID, Name: req.Name}}).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// DeleteTag 删除标签，粉丝数超过 10 万的标签无法直接删除
+func (c *Client) DeleteTag(ctx context.Context, req DeleteTagRequest) error {
+	if req.ID == 0 {
+		return fmt.Errorf("tag id is required")
+	}
+
+	_, err := Request[struct{}](c).
+		Path(tagsDeletePath).
+		Body(tagBody{Tag: Tag{ID: req.ID}}).
+		Post(ctx)
+	return err
+}
+
+// GetTagUsers 获取标签下粉丝列表的一页
+func (c *Client) GetTagUsers(ctx context.Context, req GetTagUsersRequest) (OpenIDList, error) {
+	if req.TagID == 0 {
+		return OpenIDList{}, fmt.Errorf("tagid is required")
+	}
+
+	return Request[OpenIDList](c).
+		Path(tagUserGetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// TagUsers 遍历标签下的全部粉丝 OpenID，语义同 Users
+func (c *Client) TagUsers(ctx context.Context, tagID int64) iter.Seq2[string, error] {
+	return iterateOpenIDs(func(next string) (OpenIDList, error) {
+		return c.GetTagUsers(ctx, GetTagUsersRequest{TagID: tagID, NextOpenID: next})
+	})
+}
+
+// BatchTagging 批量为用户打标签，每次最多 50 个
+func (c *Client) BatchTagging(ctx context.Context, req BatchTaggingRequest) error {
+	if err := validateBatchTagging(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(tagsBatchTaggingPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// BatchUntagging 批量为用户取消标签，每次最多 50 个
+func (c *Client) BatchUntagging(ctx context.Context, req BatchTaggingRequest) error {
+	if err := validateBatchTagging(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(tagsBatchUntaggingPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// GetUserTagIDs 获取用户身上的标签 ID 列表
+func (c *Client) GetUserTagIDs(ctx context.Context, req GetUserTagIDsRequest) (GetUserTagIDsResponse, error) {
+	if req.OpenID == "" {
+		return GetUserTagIDsResponse{}, fmt.Errorf("openid is required")
+	}
+
+	return Request[GetUserTagIDsResponse](c).
+		Path(tagsGetIDListPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// GetBlacklist 获取黑名单列表的一页，BeginOpenID 为空时从头拉取
+func (c *Client) GetBlacklist(ctx context.Context, req GetBlacklistRequest) (OpenIDList, error) {
+	return Request[OpenIDList](c).
+		Path(blacklistGetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+}
+
+// Blacklist 遍历全部黑名单 OpenID，语义同 Users
+func (c *Client) Blacklist(ctx context.Context) iter.Seq2[string, error] {
+	return iterateOpenIDs(func(next string) (OpenIDList, error) {
+		return c.GetBlacklist(ctx, GetBlacklistRequest{BeginOpenID: next})
+	})
+}
+
+// BatchBlacklist 拉黑用户，每次最多 20 个
+func (c *Client) BatchBlacklist(ctx context.Context, req BatchBlacklistRequest) error {
+	if err := validateBatchBlacklist(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(blacklistBatchPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// BatchUnblacklist 取消拉黑用户，每次最多 20 个
+func (c *Client) BatchUnblacklist(ctx context.Context, req BatchBlacklistRequest) error {
+	if err := validateBatchBlacklist(req); err != nil {
+		return err
+	}
+
+	_, err := Request[struct{}](c).
+		Path(blacklistBatchUnsetPath).
+		Body(req).
+		Idempotent(true).
+		Post(ctx)
+	return err
+}
+
+// iterateOpenIDs 按 next_openid 翻页产出 OpenID。
+// 微信在最后一页仍会返回 next_openid，需再请求一次拿到空页才算结束。
+func iterateOpenIDs(fetch func(next string) (OpenIDList, error)) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		next := ""
+		for {
+			page, err := fetch(next)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, openID := range page.Data.OpenID {
+				if !yield(openID, nil) {
+					return
+				}
+			}
+			if len(page.Data.OpenID) == 0 || page.NextOpenID == "" || page.NextOpenID == next {
+				return
+			}
+			next = page.NextOpenID
+		}
+	}
+}
+
+func validateTagName(name string) error {
+	if name == "" {
+		return fmt.Errorf("tag name is required")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameRunes {
+		return fmt.Errorf("tag name exceeds %d characters", maxTagNameRunes)
+	}
+	return nil
+}
+
+func validateBatchTagging(req BatchTaggingRequest) error {
+	if req.TagID == 0 {
+		return fmt.Errorf("tagid is required")
+	}
+	if len(req.OpenIDList) == 0 {
+		return fmt.Errorf("openid_list is required")
+	}
+	if len(req.OpenIDList) > maxBatchTaggingCount {
+		return fmt.Errorf("openid_list exceeds %d users", maxBatchTaggingCount)
+	}
+	return nil
+}
+
+func validateBatchBlacklist(req BatchBlacklistRequest) error {
+	if len(req.OpenIDList) == 0 {
+		return fmt.Errorf("openid_list is required")
+	}
+	if len(req.OpenIDList) > maxBatchBlacklistCount {
+		return fmt.Errorf("openid_list exceeds %d users", maxBatchBlacklistCount)
+	}
+	return nil
+}
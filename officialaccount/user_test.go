@@ -0,0 +1,160 @@
+package officialaccount
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ShinyNito/FunkWechat/v2/core"
+)
+
+func TestUsersIterator(t *testing.T) {
+	var calls []string
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != userGetPath {
+			http.NotFound(w, r)
+			return
+		}
+		next := r.URL.Query().Get("next_openid")
+		calls = append(calls, next)
+		switch next {
+		case "":
+			_, _ = w.Write([]byte(`{"total":3,"count":2,"data":{"openid":["o1","o2"]},"next_openid":"o2"}`))
+		case "o2":
+			_, _ = w.Write([]byte(`{"total":3,"count":1,"data":{"openid":["o3"]},"next_openid":"o3"}`))
+		default:
+			_, _ = w.Write([]byte(`{"total":3,"count":0,"next_openid":""}`))
+		}
+	})
+
+	var got []string
+	for openID, err := range client.Users(context.Background()) {
+		if err != nil {
+			t.Fatalf("iterate users: %v", err)
+		}
+		got = append(got, openID)
+	}
+	if !slices.Equal(got, []string{"o1", "o2", "o3"}) {
+		t.Fatalf("unexpected openids: %v", got)
+	}
+	if !slices.Equal(calls, []string{"", "o2", "o3"}) {
+		t.Fatalf("unexpected pages: %v", calls)
+	}
+
+	calls = nil
+	for openID := range client.Users(context.Background()) {
+		if openID == "o1" {
+			break
+		}
+	}
+	if len(calls) != 1 {
+		t.Fatalf("break should stop paging, got %d calls", len(calls))
+	}
+}
+
+func TestTagUsersIteratorError(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req GetTagUsersRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.NextOpenID == "" {
+			_, _ = w.Write([]byte(`{"count":1,"data":{"openid":["o1"]},"next_openid":"o1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":45159,"errmsg":"invalid tag id"}`))
+	})
+
+	var got []string
+	var gotErr error
+	for openID, err := range client.TagUsers(context.Background(), 100) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, openID)
+	}
+	var we *core.WechatError
+	if !slices.Equal(got, []string{"o1"}) || !errors.As(gotErr, &we) || we.ErrCode != 45159 {
+		t.Fatalf("unexpected result: %v %v", got, gotErr)
+	}
+}
+
+func TestUserAndTagAPIs(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+		}
+		switch r.URL.Path {
+		case userInfoPath:
+			if r.URL.Query().Get("openid") != "o1" || r.URL.Query().Get("lang") != "zh_CN" {
+				t.Errorf("unexpected user info query: %v", r.URL.Query())
+			}
+			_, _ = w.Write([]byte(`{"subscribe":1,"openid":"o1","tagid_list":[2,100],"subscribe_scene":"ADD_SCENE_QR_CODE","qr_scene":98765}`))
+		case userInfoBatchGetPath:
+			_, _ = w.Write([]byte(`{"user_info_list":[{"subscribe":1,"openid":"o1"},{"subscribe":0,"openid":"o2"}]}`))
+		case tagsCreatePath:
+			if body["tag"].(map[string]any)["name"] != "广东" {
+				t.Errorf("unexpected create body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"tag":{"id":134,"name":"广东"}}`))
+		case tagsBatchTaggingPath:
+			if body["tagid"] != float64(134) || len(body["openid_list"].([]any)) != 2 {
+				t.Errorf("unexpected tagging body: %v", body)
+			}
+			_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case tagsGetIDListPath:
+			_, _ = w.Write([]byte(`{"tagid_list":[134,2]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	ctx := context.Background()
+
+	info, err := client.GetUserInfo(ctx, GetUserInfoRequest{OpenID: "o1"})
+	if err != nil || info.QRScene != 98765 || len(info.TagIDList) != 2 {
+		t.Fatalf("get user info: %+v %v", info, err)
+	}
+	batch, err := client.BatchGetUserInfo(ctx, BatchGetUserInfoRequest{UserList: []BatchGetUserInfoItem{{OpenID: "o1"}, {OpenID: "o2"}}})
+	if err != nil || len(batch.UserInfoList) != 2 || batch.UserInfoList[1].Subscribe != 0 {
+		t.Fatalf("batch get user info: %+v %v", batch, err)
+	}
+	tag, err := client.CreateTag(ctx, CreateTagRequest{Name: "广东"})
+	if err != nil || tag.ID != 134 {
+		t.Fatalf("create tag: %+v %v", tag, err)
+	}
+	if err := client.BatchTagging(ctx, BatchTaggingRequest{TagID: tag.ID, OpenIDList: []string{"o1", "o2"}}); err != nil {
+		t.Fatalf("batch tagging: %v", err)
+	}
+	ids, err := client.GetUserTagIDs(ctx, GetUserTagIDsRequest{OpenID: "o1"})
+	if err != nil || !slices.Equal(ids.TagIDList, []int64{134, 2}) {
+		t.Fatalf("get tag ids: %+v %v", ids, err)
+	}
+}
+
+func TestUserAPIValidation(t *testing.T) {
+	client := newTestAPIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL.Path)
+	})
+	ctx := context.Background()
+
+	tooMany := make([]string, maxBatchTaggingCount+1)
+	cases := map[string]error{
+		"remark":    client.UpdateUserRemark(ctx, UpdateUserRemarkRequest{OpenID: "o1", Remark: strings.Repeat("备", maxUserRemarkRunes+1)}),
+		"tag name":  client.UpdateTag(ctx, UpdateTagRequest{ID: 1, Name: ""}),
+		"tagging":   client.BatchTagging(ctx, BatchTaggingRequest{TagID: 1, OpenIDList: tooMany}),
+		"untagging": client.BatchUntagging(ctx, BatchTaggingRequest{OpenIDList: []string{"o1"}}),
+		"blacklist": client.BatchBlacklist(ctx, BatchBlacklistRequest{OpenIDList: tooMany[:maxBatchBlacklistCount+1]}),
+	}
+	for name, err := range cases {
+		if err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if _, err := client.BatchGetUserInfo(ctx, BatchGetUserInfoRequest{UserList: make([]BatchGetUserInfoItem, maxBatchGetUserInfoCount+1)}); err == nil {
+		t.Fatal("expected batchget validation error")
+	}
+}